@@ -0,0 +1,29 @@
+package distributecache
+
+// ByteView 持有缓存值的一份只读字节拷贝，对外表现为不可变视图，
+// 调用方拿到的永远是底层字节的副本，不会和缓存内部共享、也不会被后续写入污染
+type ByteView struct {
+	b []byte
+}
+
+// Len 返回视图的字节数，满足 policy.Value 接口，用于容量统计
+func (v ByteView) Len() int {
+	return len(v.b)
+}
+
+// ByteSlice 以拷贝的形式返回底层数据，防止调用方修改会影响到缓存内部的数据
+func (v ByteView) ByteSlice() []byte {
+	return cloneBytes(v.b)
+}
+
+// String 以字符串形式返回数据，必要时会拷贝
+func (v ByteView) String() string {
+	return string(v.b)
+}
+
+// cloneBytes 拷贝一份字节切片，避免缓存内部的数据被外部引用持有并修改
+func cloneBytes(b []byte) []byte {
+	c := make([]byte, len(b))
+	copy(c, b)
+	return c
+}
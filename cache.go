@@ -2,42 +2,101 @@ package distributecache
 
 // 保证并发安全
 import (
-	"sync"
+	"hash/fnv"
+	"time"
 
-	"github.com/distributeCache/lru"
+	"github.com/distributeCache/policy"
 )
 
-// 缓存类实体
-// 包含了一个并发锁和 LRU 缓存的新层封
+// shardCount 是分片数量，取 2 的幂是为了能用按位与代替取模来路由，
+// 256 个分片在单个全局锁成为瓶颈的高并发场景下能显著减少锁竞争。
+// 代价是容量预算的粒度变粗了：cacheBytes 会被均分到每个 shard
+// （见 shardBytes），对总预算小于 shardCount 字节的 Group，实际生效的
+// 总容量会被向上抬高到 shardCount 字节左右，不再能精确到字节级别
+const shardCount = 256
+
+// CacheStats 记录一个 cache 实例（所有分片汇总后）的运行状态，便于观测命中率和容量使用情况
+type CacheStats struct {
+	Hits      int64 // 命中次数
+	Misses    int64 // 未命中次数
+	Bytes     int64 // 当前占用的字节数
+	Evictions int64 // 淘汰次数
+	Len       int   // 当前条目数量
+}
+
+// cache 是对外暴露的缓存入口，内部按 key 的哈希分散到 shardCount 个 shard 上，
+// 每个 shard 各自持有独立的淘汰策略实例和互斥锁，把原来单个大锁的竞争分散开来。
+// factory 决定底层使用哪种淘汰策略（LRU/LFU/FIFO/TinyLFU），未设置时默认使用 LRU
 type cache struct {
-	mu         sync.Mutex // 用于保证缓存操作的并发安全
-	lru        *lru.Cache // 包含 LRU 缓存的指针
-	cacheBytes int64      // 最大可使用的缓存容量
+	cacheBytes int64          // 总容量预算，均分到每个 shard
+	factory    policy.Factory // 延迟创建 shard 的 policy 时使用的工厂，nil 表示使用默认 LRU
+	shards     [shardCount]shard
 }
 
-// add 函数用于添加一个键值对应到缓存中
-// 在添加前使用并发锁确保并发安全
-// 如果 LRU 缓存还没有创建，则将它延迟创建
-func (c *cache) add(key string, value ByteView) {
-	c.mu.Lock()         // 上锁确保下面的操作并发安全
-	defer c.mu.Unlock() // 在函数返回时释放锁
-	if c.lru == nil {
-		c.lru = lru.NewCache(c.cacheBytes, nil) // 延迟创建 LRU 实例，创建时还没有使用存储过
+// shardFor 按 fnv32(key) & (shardCount-1) 选出 key 所在的分片
+func (c *cache) shardFor(key string) *shard {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return &c.shards[h.Sum32()&(shardCount-1)]
+}
+
+// shardBytes 把总容量预算均分到每个 shard。0 按本仓库的约定表示“不限制容量”，
+// 必须原样保留；其余情况下至少分给每个 shard 1 字节，否则 cacheBytes < shardCount
+// 时整除会截断成 0，而 0 恰好又是“不限制”的含义，会让配置的小容量预算形同虚设
+func (c *cache) shardBytes() int64 {
+	if c.cacheBytes <= 0 {
+		return 0
+	}
+	if b := c.cacheBytes / shardCount; b > 0 {
+		return b
 	}
-	c.lru.Add(key, value) // 添加键值对应
+	return 1
+}
+
+// add 函数用于添加一个键值对应到缓存中，不设置过期时间
+func (c *cache) add(key string, value ByteView) {
+	c.shardFor(key).add(key, value, c.shardBytes(), c.factory)
+}
+
+// addWithTTL 函数用于添加一个键值对应到缓存中，并在 ttl > 0 时设置过期时间
+func (c *cache) addWithTTL(key string, value ByteView, ttl time.Duration) {
+	c.shardFor(key).addWithTTL(key, value, ttl, c.shardBytes(), c.factory)
 }
 
 // get 函数通过键获取对应的值
-// 如果 LRU 还没有创建，则返回空
-// 在获取前使用并发锁保证并发安全
 func (c *cache) get(key string) (value ByteView, ok bool) {
-	c.mu.Lock()         // 上锁确保下面的操作并发安全
-	defer c.mu.Unlock() // 在函数返回时释放锁
-	if c.lru == nil {
-		return // 如果 LRU 还没有创建，返回空值
+	return c.shardFor(key).get(key)
+}
+
+// getWithExpiration 和 get 相同，但额外返回这个 key 的过期时间（零值表示没有设置 TTL）；
+// 供需要把 TTL 透传给远程同伴的场景使用（比如通过 hotCache 转发时带上来源的过期时间）
+func (c *cache) getWithExpiration(key string) (value ByteView, expiration time.Time, ok bool) {
+	return c.shardFor(key).getWithExpiration(key)
+}
+
+// peekExpiration 只读取 key 的过期时间，不影响命中/未命中统计
+func (c *cache) peekExpiration(key string) (expiration time.Time, ok bool) {
+	return c.shardFor(key).peekExpiration(key)
+}
+
+// sweepExpired 清理所有分片中已经过期的条目，供后台 janitor 周期性调用
+func (c *cache) sweepExpired(now time.Time) (removed int) {
+	for i := range c.shards {
+		removed += c.shards[i].sweepExpired(now)
 	}
-	if v, ok := c.lru.Get(key); ok {
-		return v.(ByteView), ok // 转为 ByteView 类型并返回
+	return removed
+}
+
+// stats 汇总所有分片的命中/未命中/容量/淘汰情况
+func (c *cache) stats() CacheStats {
+	var total CacheStats
+	for i := range c.shards {
+		s := c.shards[i].stats()
+		total.Hits += s.Hits
+		total.Misses += s.Misses
+		total.Bytes += s.Bytes
+		total.Evictions += s.Evictions
+		total.Len += s.Len
 	}
-	return
+	return total
 }
@@ -0,0 +1,96 @@
+package distributecache
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/distributeCache/lru"
+)
+
+// singleMutexCache 是分片之前的基线实现：一把全局锁包住唯一的一个 policy 实例，
+// 和分片引入之前的 distributecache.cache 结构完全对应，用来在基准测试里
+// 公平地衡量分片带来的吞吐提升——每次操作只应该付出一次加锁的代价，
+// 而不是像包一层 shards[0] 那样额外多付一次 shard 自己的锁
+type singleMutexCache struct {
+	mu         sync.Mutex
+	cacheBytes int64
+	lru        *lru.Cache
+}
+
+func (s *singleMutexCache) add(key string, value ByteView) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.lru == nil {
+		s.lru = lru.NewCache(s.cacheBytes, nil)
+	}
+	s.lru.Add(key, value)
+}
+
+func (s *singleMutexCache) get(key string) (ByteView, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.lru == nil {
+		return ByteView{}, false
+	}
+	if v, ok := s.lru.Get(key); ok {
+		return v.(ByteView), true
+	}
+	return ByteView{}, false
+}
+
+// benchKeys 生成基准测试用的 key 集合
+func benchKeys(n int) []string {
+	keys := make([]string, n)
+	for i := 0; i < n; i++ {
+		keys[i] = "key" + strconv.Itoa(i)
+	}
+	return keys
+}
+
+// BenchmarkCacheParallel 对比分片 cache 与单锁 baseline 在并行 Get/Add 混合负载下的吞吐，
+// 分片版本把锁竞争分散到 shardCount 个 shard 上，理论上 GOMAXPROCS 越大优势越明显
+func BenchmarkCacheParallel(b *testing.B) {
+	const maxBytes = 1 << 20
+	keys := benchKeys(1024)
+
+	b.Run("Sharded", func(b *testing.B) {
+		c := &cache{cacheBytes: maxBytes}
+		for _, k := range keys {
+			c.add(k, ByteView{b: []byte(k)})
+		}
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			i := 0
+			for pb.Next() {
+				k := keys[i%len(keys)]
+				if i%10 == 0 {
+					c.add(k, ByteView{b: []byte(k)})
+				} else {
+					c.get(k)
+				}
+				i++
+			}
+		})
+	})
+
+	b.Run("SingleMutex", func(b *testing.B) {
+		s := &singleMutexCache{cacheBytes: maxBytes}
+		for _, k := range keys {
+			s.add(k, ByteView{b: []byte(k)})
+		}
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			i := 0
+			for pb.Next() {
+				k := keys[i%len(keys)]
+				if i%10 == 0 {
+					s.add(k, ByteView{b: []byte(k)})
+				} else {
+					s.get(k)
+				}
+				i++
+			}
+		})
+	})
+}
@@ -0,0 +1,101 @@
+package distributecache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestShardForIsDeterministic(t *testing.T) {
+	c := &cache{cacheBytes: 1 << 20}
+	first := c.shardFor("some-key")
+	for i := 0; i < 10; i++ {
+		if got := c.shardFor("some-key"); got != first {
+			t.Fatalf("shardFor(%q) = %p, want stable %p across repeated calls", "some-key", got, first)
+		}
+	}
+}
+
+// TestShardBytesClampsSmallBudgetToAtLeastOnePerShard 覆盖 review 指出的那个 bug：
+// cacheBytes 小于 shardCount 时，如果直接整除会截断成 0，而 0 又正好是“不限制容量”
+// 的哨兵值，导致一个本应很小的预算配置形同虚设地变成了无限容量。
+func TestShardBytesClampsSmallBudgetToAtLeastOnePerShard(t *testing.T) {
+	c := &cache{cacheBytes: 100} // 100 < shardCount(256)，朴素整除会得到 0
+	if got := c.shardBytes(); got != 1 {
+		t.Errorf("shardBytes() = %d, want 1", got)
+	}
+}
+
+func TestShardBytesPreservesUnlimitedSentinel(t *testing.T) {
+	c := &cache{cacheBytes: 0}
+	if got := c.shardBytes(); got != 0 {
+		t.Errorf("shardBytes() = %d, want 0 (cacheBytes<=0 must stay unlimited)", got)
+	}
+}
+
+func TestShardBytesDividesLargeBudgetAcrossShards(t *testing.T) {
+	c := &cache{cacheBytes: shardCount * 10}
+	if got := c.shardBytes(); got != 10 {
+		t.Errorf("shardBytes() = %d, want 10", got)
+	}
+}
+
+// TestCacheStatsAggregatesAcrossShards 验证 stats() 把各个分片的命中/未命中/
+// 条目数汇总到了一起，而不是只反映某一个分片。
+func TestCacheStatsAggregatesAcrossShards(t *testing.T) {
+	c := &cache{cacheBytes: 1 << 20}
+
+	keys := []string{"k0", "k1", "k2", "k3", "k4", "k5", "k6", "k7", "k8", "k9"}
+	for _, k := range keys {
+		c.add(k, ByteView{b: []byte("v")})
+	}
+	for _, k := range keys {
+		if _, ok := c.get(k); !ok {
+			t.Fatalf("get(%q) miss right after add", k)
+		}
+	}
+	c.get("missing-1")
+	c.get("missing-2")
+
+	stats := c.stats()
+	if stats.Len != len(keys) {
+		t.Errorf("stats.Len = %d, want %d", stats.Len, len(keys))
+	}
+	if stats.Hits != int64(len(keys)) {
+		t.Errorf("stats.Hits = %d, want %d", stats.Hits, len(keys))
+	}
+	if stats.Misses != 2 {
+		t.Errorf("stats.Misses = %d, want 2", stats.Misses)
+	}
+}
+
+// TestCacheSweepExpiredAcrossShardsRemovesOnlyExpiredEntries 确保按分片清理过期条目时
+// 结果是跨所有分片汇总的，而不是只清理了 key 恰好落入的那一个分片。
+func TestCacheSweepExpiredAcrossShardsRemovesOnlyExpiredEntries(t *testing.T) {
+	c := &cache{cacheBytes: 1 << 20}
+	expiredKeys := []string{"e0", "e1", "e2", "e3", "e4"}
+	foreverKeys := []string{"f0", "f1", "f2", "f3", "f4"}
+
+	for _, k := range expiredKeys {
+		c.addWithTTL(k, ByteView{b: []byte("v")}, time.Millisecond)
+	}
+	for _, k := range foreverKeys {
+		c.add(k, ByteView{b: []byte("v")})
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	removed := c.sweepExpired(time.Now())
+	if removed != len(expiredKeys) {
+		t.Errorf("sweepExpired removed = %d, want %d", removed, len(expiredKeys))
+	}
+	for _, k := range expiredKeys {
+		if _, ok := c.get(k); ok {
+			t.Errorf("%q should have been swept", k)
+		}
+	}
+	for _, k := range foreverKeys {
+		if _, ok := c.get(k); !ok {
+			t.Errorf("%q should not have been swept", k)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package distributecache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCacheAddWithTTLExpires(t *testing.T) {
+	c := &cache{cacheBytes: 1 << 20}
+	c.addWithTTL("k1", ByteView{b: []byte("v1")}, 10*time.Millisecond)
+
+	if _, ok := c.get("k1"); !ok {
+		t.Fatal("k1 should be present immediately after being added")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	if _, ok := c.get("k1"); ok {
+		t.Error("k1 should have expired by now")
+	}
+}
+
+func TestCacheAddWithoutTTLNeverExpires(t *testing.T) {
+	c := &cache{cacheBytes: 1 << 20}
+	c.add("k1", ByteView{b: []byte("v1")})
+
+	time.Sleep(20 * time.Millisecond)
+
+	if v, ok := c.get("k1"); !ok || v.String() != "v1" {
+		t.Errorf("get(k1) = (%v, %v), want (v1, true)", v, ok)
+	}
+}
+
+func TestCacheGetWithExpirationReportsDeadline(t *testing.T) {
+	c := &cache{cacheBytes: 1 << 20}
+	before := time.Now()
+	c.addWithTTL("k1", ByteView{b: []byte("v1")}, time.Minute)
+	after := time.Now()
+
+	_, expiration, ok := c.getWithExpiration("k1")
+	if !ok {
+		t.Fatal("k1 should be present")
+	}
+	if expiration.Before(before.Add(time.Minute)) || expiration.After(after.Add(time.Minute)) {
+		t.Errorf("expiration = %v, want roughly between %v and %v",
+			expiration, before.Add(time.Minute), after.Add(time.Minute))
+	}
+
+	// peekExpiration 应该返回同样的过期时间，且不影响命中/未命中统计
+	statsBefore := c.stats()
+	peeked, ok := c.peekExpiration("k1")
+	if !ok || !peeked.Equal(expiration) {
+		t.Errorf("peekExpiration = (%v, %v), want (%v, true)", peeked, ok, expiration)
+	}
+	statsAfter := c.stats()
+	if statsAfter.Hits != statsBefore.Hits || statsAfter.Misses != statsBefore.Misses {
+		t.Error("peekExpiration should not affect hit/miss stats")
+	}
+}
+
+func TestCacheSweepExpiredRemovesOnlyExpiredEntries(t *testing.T) {
+	c := &cache{cacheBytes: 1 << 20}
+	c.addWithTTL("expired", ByteView{b: []byte("v")}, time.Millisecond)
+	c.add("forever", ByteView{b: []byte("v")})
+
+	time.Sleep(20 * time.Millisecond)
+
+	removed := c.sweepExpired(time.Now())
+	if removed != 1 {
+		t.Errorf("sweepExpired removed = %d, want 1", removed)
+	}
+	if _, ok := c.get("expired"); ok {
+		t.Error("expired should have been swept")
+	}
+	if _, ok := c.get("forever"); !ok {
+		t.Error("forever should not have been swept")
+	}
+}
+
+func TestGroupJanitorSweepsExpiredEntries(t *testing.T) {
+	g := NewGroup("janitor-test", 1<<20, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		return nil, ErrNotFound
+	}))
+	g.mainCache.addWithTTL("k1", ByteView{b: []byte("v1")}, 5*time.Millisecond)
+
+	stop := g.StartJanitor(10 * time.Millisecond)
+	defer stop()
+
+	time.Sleep(60 * time.Millisecond)
+
+	// get 本身也会把过期条目当作未命中，所以先用不触发清理的 stats 确认条目已被后台清掉
+	if n := g.mainCache.stats().Len; n != 0 {
+		t.Errorf("mainCache Len = %d after janitor ran, want 0", n)
+	}
+	if _, ok := g.mainCache.get("k1"); ok {
+		t.Error("StartJanitor should have swept the expired entry in the background")
+	}
+}
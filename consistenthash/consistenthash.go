@@ -15,11 +15,13 @@ type Hash func(data []byte) uint32
 // replicas: 虚拟节点的倍数
 // keys: 哈希环（虚拟节点的哈希值）
 // hashMap: 虚拟节点和实际节点的对应关系
+// nodeReplicas: 每个真实节点实际对应的虚拟节点数量，Remove 和 AddWeighted 需要用到
 type Map struct {
-	hash     Hash
-	replicas int            // 虚拟节点倍数
-	keys     []int          // 哈希环
-	hashMap  map[int]string // 虚拟节点与实际节点的映射
+	hash         Hash
+	replicas     int            // 虚拟节点倍数
+	keys         []int          // 哈希环
+	hashMap      map[int]string // 虚拟节点与实际节点的映射
+	nodeReplicas map[string]int // 每个真实节点对应的虚拟节点数量
 }
 
 // NewHash 函数用于创建一个新的哈希场景
@@ -27,9 +29,10 @@ type Map struct {
 // fn: 哈希函数，用来计算哈希值
 func NewHash(replicas int, fn Hash) *Map {
 	m := &Map{
-		hash:     fn,
-		replicas: replicas,
-		hashMap:  make(map[int]string),
+		hash:         fn,
+		replicas:     replicas,
+		hashMap:      make(map[int]string),
+		nodeReplicas: make(map[string]int),
 	}
 	if m.hash == nil {
 		m.hash = crc32.ChecksumIEEE // 如果未指定哈希函数，则使用默认的循环冷余校验算法
@@ -37,17 +40,59 @@ func NewHash(replicas int, fn Hash) *Map {
 	return m
 }
 
+// removeNode 清除一个真实节点当前在哈希环上的所有虚拟节点，
+// addNode 在重新添加/加权前，以及 Remove 在彻底删除节点时，都复用这个逻辑
+func (m *Map) removeNode(node string) {
+	replicas, ok := m.nodeReplicas[node]
+	if !ok {
+		return // 节点不存在，忽略
+	}
+	for i := 0; i < replicas; i++ {
+		hash := int(m.hash([]byte(strconv.Itoa(i) + node)))
+		idx := sort.SearchInts(m.keys, hash)
+		if idx < len(m.keys) && m.keys[idx] == hash {
+			m.keys = append(m.keys[:idx], m.keys[idx+1:]...) // 从哈希环中删除该虚拟节点
+		}
+		delete(m.hashMap, hash)
+	}
+	delete(m.nodeReplicas, node)
+}
+
+// addNode 为一个真实节点生成指定数量的虚拟节点，Add 和 AddWeighted 共用这个逻辑
+// 若该节点已经在环上（重复 Add 或用新权重调用 AddWeighted），先清掉它原有的虚拟节点，
+// 避免环上残留旧哈希值导致的幽灵节点
+func (m *Map) addNode(node string, replicas int) {
+	m.removeNode(node)
+	for i := 0; i < replicas; i++ {
+		hash := int(m.hash([]byte(strconv.Itoa(i) + node))) // 生成虚拟节点的哈希值
+		m.keys = append(m.keys, hash)                       // 将哈希值添加到哈希环中
+		m.hashMap[hash] = node                              // 将哈希值与实际节点应用
+	}
+	m.nodeReplicas[node] = replicas
+	sort.Ints(m.keys) // 按哈希值序列排序
+}
+
 // Add 方法用于添加实际节点，并为每个节点生成指定倍数的虚拟节点
 // keys: 可变的实际节点
 func (m *Map) Add(keys ...string) {
 	for _, key := range keys {
-		for i := 0; i < m.replicas; i++ {
-			hash := int(m.hash([]byte(strconv.Itoa(i) + key))) // 生成虚拟节点的哈希值
-			m.keys = append(m.keys, hash)                      // 将哈希值添加到哈希环中
-			m.hashMap[hash] = key                              // 将哈希值与实际节点应用
-		}
+		m.addNode(key, m.replicas)
+	}
+}
+
+// AddWeighted 方法按权重添加实际节点，权重越大，该节点分到的虚拟节点越多，
+// 从而在哈希环上占据更多位置，承担更多流量；适合异构节点（如机器配置不同）的场景
+// weight: 相对于默认 replicas 的倍数，weight=2 表示虚拟节点数量翻倍
+func (m *Map) AddWeighted(node string, weight int) {
+	m.addNode(node, m.replicas*weight)
+}
+
+// Remove 方法从哈希环中移除指定的真实节点，包括它所有的虚拟节点
+// keys: 需要移除的真实节点
+func (m *Map) Remove(keys ...string) {
+	for _, key := range keys {
+		m.removeNode(key)
 	}
-	sort.Ints(m.keys) // 按哈希值序列排序
 }
 
 // Get 方法根据指定的 key 找到最近的节点
@@ -64,3 +109,26 @@ func (m *Map) Get(key string) string {
 
 	return m.hashMap[m.keys[idx%len(m.keys)]] // 返回最近节点的实际节点
 }
+
+// GetN 方法从指定 key 在哈希环上的位置开始，顺时针返回最多 n 个不同的真实节点，
+// 用于需要副本/故障转移的场景：第一个节点不可用时，调用方可以依次尝试后面的节点
+func (m *Map) GetN(key string, n int) []string {
+	if len(m.keys) == 0 || n <= 0 {
+		return nil
+	}
+	hash := int(m.hash([]byte(key)))
+	idx := sort.Search(len(m.keys), func(i int) bool {
+		return m.keys[i] >= hash
+	})
+
+	seen := make(map[string]bool, n)
+	nodes := make([]string, 0, n)
+	for i := 0; i < len(m.keys) && len(nodes) < n; i++ {
+		node := m.hashMap[m.keys[(idx+i)%len(m.keys)]]
+		if !seen[node] {
+			seen[node] = true
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
@@ -0,0 +1,107 @@
+package consistenthash
+
+import (
+	"strconv"
+	"testing"
+)
+
+// 用一个可预测的哈希函数代替 crc32，方便断言具体落在哪个虚拟节点上：
+// 节点名本身就是它的哈希值，例如虚拟节点 "2" 的哈希就是 2
+func mockHash(data []byte) uint32 {
+	n, _ := strconv.Atoi(string(data))
+	return uint32(n)
+}
+
+func TestGetDistributesToNearestNode(t *testing.T) {
+	m := NewHash(1, mockHash) // replicas=1，每个节点只有一个虚拟节点，哈希就是 "0"+node 拼接后的整数（如 "06" → 6）
+	m.Add("6", "4", "2")
+
+	cases := map[string]string{
+		"2":  "2",
+		"11": "2", // 环上找不到 >=11 的节点时，回绕到最小的节点 2
+		"23": "2",
+		"27": "2",
+	}
+	for key, want := range cases {
+		if got := m.Get(key); got != want {
+			t.Errorf("Get(%q) = %q, want %q", key, got, want)
+		}
+	}
+
+	m.Add("8")
+	if got := m.Get("27"); got != "2" { // 新增的 8 不在 27 和最小节点之间，结果不受影响
+		t.Errorf("after adding 8: Get(27) = %q, want %q", got, "2")
+	}
+}
+
+func TestRemoveDeletesAllReplicas(t *testing.T) {
+	m := NewHash(3, nil)
+	m.Add("a", "b", "c")
+	if got := m.Get("somekey"); got == "" {
+		t.Fatal("expected a node before Remove")
+	}
+
+	m.Remove("a", "b", "c")
+	if got := m.Get("somekey"); got != "" {
+		t.Errorf("Get after removing all nodes = %q, want empty", got)
+	}
+	if len(m.keys) != 0 || len(m.hashMap) != 0 || len(m.nodeReplicas) != 0 {
+		t.Errorf("Remove left residual state: keys=%d hashMap=%d nodeReplicas=%d",
+			len(m.keys), len(m.hashMap), len(m.nodeReplicas))
+	}
+}
+
+func TestRemoveOnlyAffectsGivenNode(t *testing.T) {
+	m := NewHash(50, nil)
+	m.Add("a", "b")
+	m.Remove("a")
+
+	if _, ok := m.nodeReplicas["a"]; ok {
+		t.Error("node a should have been removed")
+	}
+	if _, ok := m.nodeReplicas["b"]; !ok {
+		t.Error("node b should still be present")
+	}
+	// 环上剩余的虚拟节点数应该正好等于 b 的虚拟节点数
+	if len(m.keys) != m.nodeReplicas["b"] {
+		t.Errorf("keys len = %d, want %d", len(m.keys), m.nodeReplicas["b"])
+	}
+}
+
+func TestAddWeightedGivesMoreReplicas(t *testing.T) {
+	m := NewHash(10, nil)
+	m.AddWeighted("heavy", 3)
+	m.Add("light")
+
+	if got, want := m.nodeReplicas["heavy"], 30; got != want {
+		t.Errorf("heavy node replicas = %d, want %d", got, want)
+	}
+	if got, want := m.nodeReplicas["light"], 10; got != want {
+		t.Errorf("light node replicas = %d, want %d", got, want)
+	}
+}
+
+func TestGetNReturnsDistinctNodesInRingOrder(t *testing.T) {
+	m := NewHash(1, mockHash)
+	m.Add("6", "4", "2")
+
+	nodes := m.GetN("11", 2)
+	if len(nodes) != 2 {
+		t.Fatalf("GetN returned %d nodes, want 2: %v", len(nodes), nodes)
+	}
+	if nodes[0] != "2" || nodes[1] != "4" {
+		t.Errorf("GetN(11, 2) = %v, want [2 4]", nodes)
+	}
+
+	// 请求的副本数超过真实节点数时，应该只返回去重后的真实节点数量
+	if all := m.GetN("11", 10); len(all) != 3 {
+		t.Errorf("GetN(11, 10) = %v, want 3 distinct nodes", all)
+	}
+}
+
+func TestGetNOnEmptyRing(t *testing.T) {
+	m := NewHash(3, nil)
+	if nodes := m.GetN("key", 3); nodes != nil {
+		t.Errorf("GetN on empty ring = %v, want nil", nodes)
+	}
+}
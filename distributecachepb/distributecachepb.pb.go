@@ -0,0 +1,242 @@
+// 本文件不是 protoc-gen-go 生成的代码：沙箱里没有 protoc，
+// 这里按 distributecachepb.proto 描述的字段手写了一份兼容 proto3
+// wire format 的最小编解码器（tag + varint / length-delimited），
+// 而不是引入 google.golang.org/protobuf 这种要求实现 ProtoReflect()
+// 的完整 v2 运行时——两者不能混用，之前误用 proto.Marshal/Unmarshal
+// 包在这两个手写结构体上，根本编译不过
+package distributecachepb
+
+import "errors"
+
+// wire type，和 proto3 的编码规则保持一致
+const (
+	wireVarint = 0
+	wireBytes  = 2
+)
+
+func appendTag(buf []byte, fieldNum int, wireType uint64) []byte {
+	return appendVarint(buf, uint64(fieldNum)<<3|wireType)
+}
+
+func appendVarint(buf []byte, v uint64) []byte {
+	for v >= 0x80 {
+		buf = append(buf, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(buf, byte(v))
+}
+
+func appendBytesField(buf []byte, fieldNum int, b []byte) []byte {
+	if len(b) == 0 {
+		return buf // proto3 对零值字段不编码
+	}
+	buf = appendTag(buf, fieldNum, wireBytes)
+	buf = appendVarint(buf, uint64(len(b)))
+	return append(buf, b...)
+}
+
+func appendStringField(buf []byte, fieldNum int, s string) []byte {
+	return appendBytesField(buf, fieldNum, []byte(s))
+}
+
+func appendVarintField(buf []byte, fieldNum int, v int64) []byte {
+	if v == 0 {
+		return buf // proto3 对零值字段不编码
+	}
+	buf = appendTag(buf, fieldNum, wireVarint)
+	return appendVarint(buf, uint64(v))
+}
+
+// readVarint 从 buf[i:] 解析一个 varint，返回解析出的值和下一个未读字节的下标
+func readVarint(buf []byte, i int) (uint64, int, error) {
+	var v uint64
+	var shift uint
+	for {
+		if i >= len(buf) {
+			return 0, 0, errors.New("distributecachepb: truncated varint")
+		}
+		b := buf[i]
+		i++
+		v |= uint64(b&0x7f) << shift
+		if b < 0x80 {
+			return v, i, nil
+		}
+		shift += 7
+	}
+}
+
+// skipField 跳过一个当前解码器不认识的字段，而不是直接报错，
+// 这样未来给 Request/Response 加新字段时，旧的解码器仍然能跳过它们继续读完消息
+func skipField(buf []byte, i int, wireType uint64) (int, error) {
+	switch wireType {
+	case wireVarint:
+		_, next, err := readVarint(buf, i)
+		if err != nil {
+			return 0, err
+		}
+		return next, nil
+	case wireBytes:
+		length, next, err := readVarint(buf, i)
+		if err != nil {
+			return 0, err
+		}
+		i = next
+		if i+int(length) > len(buf) {
+			return 0, errors.New("distributecachepb: truncated field while skipping")
+		}
+		return i + int(length), nil
+	default:
+		return 0, errors.New("distributecachepb: unsupported wire type while skipping unknown field")
+	}
+}
+
+// Request 描述一次同伴间的取值请求
+type Request struct {
+	Group string
+	Key   string
+}
+
+func (m *Request) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+func (m *Request) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+// Marshal 把 Request 编码为 proto3 wire format 字节流
+func (m *Request) Marshal() ([]byte, error) {
+	var buf []byte
+	buf = appendStringField(buf, 1, m.Group)
+	buf = appendStringField(buf, 2, m.Key)
+	return buf, nil
+}
+
+// Unmarshal 从 proto3 wire format 字节流解码出 Request 的字段
+func (m *Request) Unmarshal(buf []byte) error {
+	*m = Request{}
+	i := 0
+	for i < len(buf) {
+		tag, next, err := readVarint(buf, i)
+		if err != nil {
+			return err
+		}
+		i = next
+		fieldNum, wireType := int(tag>>3), tag&0x7
+		switch fieldNum {
+		case 1, 2:
+			if wireType != wireBytes {
+				return errors.New("distributecachepb: unsupported wire type in Request")
+			}
+			length, next, err := readVarint(buf, i)
+			if err != nil {
+				return err
+			}
+			i = next
+			if i+int(length) > len(buf) {
+				return errors.New("distributecachepb: truncated field in Request")
+			}
+			value := buf[i : i+int(length)]
+			i += int(length)
+			if fieldNum == 1 {
+				m.Group = string(value)
+			} else {
+				m.Key = string(value)
+			}
+		default:
+			// 未识别的字段：跳过而不是报错，这样以后给 Request 加字段不会
+			// 导致还在跑旧代码的客户端解码失败
+			next, err := skipField(buf, i, wireType)
+			if err != nil {
+				return err
+			}
+			i = next
+		}
+	}
+	return nil
+}
+
+// Response 携带取值请求的结果
+type Response struct {
+	Value []byte
+	// TtlSeconds 是这份数据在来源节点上剩余的存活时间，0 表示没有设置 TTL；
+	// 让取值的一方（通常是写入 hotCache 的节点）可以沿用来源的过期时间，而不是凭空给一个新的
+	TtlSeconds int64
+}
+
+func (m *Response) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (m *Response) GetTtlSeconds() int64 {
+	if m != nil {
+		return m.TtlSeconds
+	}
+	return 0
+}
+
+// Marshal 把 Response 编码为 proto3 wire format 字节流
+func (m *Response) Marshal() ([]byte, error) {
+	var buf []byte
+	buf = appendBytesField(buf, 1, m.Value)
+	buf = appendVarintField(buf, 2, m.TtlSeconds)
+	return buf, nil
+}
+
+// Unmarshal 从 proto3 wire format 字节流解码出 Response 的字段
+func (m *Response) Unmarshal(buf []byte) error {
+	*m = Response{}
+	i := 0
+	for i < len(buf) {
+		tag, next, err := readVarint(buf, i)
+		if err != nil {
+			return err
+		}
+		i = next
+		fieldNum, wireType := int(tag>>3), tag&0x7
+		switch fieldNum {
+		case 1:
+			if wireType != wireBytes {
+				return errors.New("distributecachepb: unsupported wire type for Response.Value")
+			}
+			length, next, err := readVarint(buf, i)
+			if err != nil {
+				return err
+			}
+			i = next
+			if i+int(length) > len(buf) {
+				return errors.New("distributecachepb: truncated field in Response")
+			}
+			m.Value = append([]byte(nil), buf[i:i+int(length)]...)
+			i += int(length)
+		case 2:
+			if wireType != wireVarint {
+				return errors.New("distributecachepb: unsupported wire type for Response.TtlSeconds")
+			}
+			v, next, err := readVarint(buf, i)
+			if err != nil {
+				return err
+			}
+			i = next
+			m.TtlSeconds = int64(v)
+		default:
+			// 未识别的字段：跳过而不是报错，这样以后给 Response 加字段
+			// (比如 error code) 不会导致还在跑旧代码的客户端解码失败
+			next, err := skipField(buf, i, wireType)
+			if err != nil {
+				return err
+			}
+			i = next
+		}
+	}
+	return nil
+}
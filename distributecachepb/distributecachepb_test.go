@@ -0,0 +1,51 @@
+package distributecachepb
+
+import "testing"
+
+func TestRequestMarshalUnmarshal(t *testing.T) {
+	in := &Request{Group: "scores", Key: "Tom"}
+	buf, err := in.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	out := &Request{}
+	if err := out.Unmarshal(buf); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if out.GetGroup() != in.GetGroup() || out.GetKey() != in.GetKey() {
+		t.Fatalf("round-trip mismatch: got %+v, want %+v", out, in)
+	}
+}
+
+func TestResponseMarshalUnmarshal(t *testing.T) {
+	in := &Response{Value: []byte("hello"), TtlSeconds: 42}
+	buf, err := in.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	out := &Response{}
+	if err := out.Unmarshal(buf); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if string(out.GetValue()) != string(in.GetValue()) || out.GetTtlSeconds() != in.GetTtlSeconds() {
+		t.Fatalf("round-trip mismatch: got %+v, want %+v", out, in)
+	}
+}
+
+func TestResponseZeroValueFieldsOmitted(t *testing.T) {
+	// proto3 的零值字段不应该被编码，确认空 Response 编码为空字节流
+	buf, err := (&Response{}).Marshal()
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if len(buf) != 0 {
+		t.Fatalf("expected zero-value Response to encode to empty bytes, got %v", buf)
+	}
+	out := &Response{Value: []byte("stale"), TtlSeconds: 1}
+	if err := out.Unmarshal(buf); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if out.GetValue() != nil || out.GetTtlSeconds() != 0 {
+		t.Fatalf("Unmarshal of empty buffer should reset fields, got %+v", out)
+	}
+}
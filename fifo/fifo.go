@@ -0,0 +1,93 @@
+// Package fifo 实现了先进先出（FIFO）的淘汰策略
+package fifo
+
+import (
+	"container/list"
+
+	"github.com/distributeCache/policy"
+)
+
+type entry struct {
+	key   string
+	value policy.Value
+}
+
+// Cache 是 policy.Policy 的 FIFO 实现：按写入顺序淘汰，Get 不会影响淘汰顺序
+type Cache struct {
+	maxBytes  int64
+	usedBytes int64
+	ll        *list.List
+	cache     map[string]*list.Element
+	OnEvicted func(key string, value policy.Value)
+}
+
+func NewCache(maxBytes int64, onEvicted func(string, policy.Value)) *Cache {
+	return &Cache{
+		maxBytes:  maxBytes,
+		ll:        list.New(),
+		cache:     make(map[string]*list.Element),
+		OnEvicted: onEvicted,
+	}
+}
+
+// NewPolicy 将 NewCache 包装为 policy.Factory，供 Group 按策略选择 FIFO
+func NewPolicy(maxBytes int64, onEvicted policy.OnEvicted) policy.Policy {
+	return NewCache(maxBytes, onEvicted)
+}
+
+// Get 不调整队列顺序，这正是 FIFO 和 LRU 的区别所在
+func (c *Cache) Get(key string) (policy.Value, bool) {
+	if ele, ok := c.cache[key]; ok {
+		return ele.Value.(*entry).value, true
+	}
+	return nil, false
+}
+
+func (c *Cache) Add(key string, value policy.Value) {
+	if ele, ok := c.cache[key]; ok {
+		kv := ele.Value.(*entry)
+		c.usedBytes += int64(value.Len()) - int64(kv.value.Len())
+		kv.value = value
+	} else {
+		ele := c.ll.PushBack(&entry{key, value})
+		c.cache[key] = ele
+		c.usedBytes += int64(len(key)) + int64(value.Len())
+	}
+	for c.maxBytes > 0 && c.usedBytes > c.maxBytes {
+		c.removeOldest()
+	}
+}
+
+// removeOldest 淘汰最早写入的条目，即队列头部
+func (c *Cache) removeOldest() {
+	ele := c.ll.Front()
+	if ele == nil {
+		return
+	}
+	c.ll.Remove(ele)
+	kv := ele.Value.(*entry)
+	delete(c.cache, kv.key)
+	c.usedBytes -= int64(len(kv.key)) + int64(kv.value.Len())
+	if c.OnEvicted != nil {
+		c.OnEvicted(kv.key, kv.value)
+	}
+}
+
+func (c *Cache) Remove(key string) {
+	ele, ok := c.cache[key]
+	if !ok {
+		return
+	}
+	c.ll.Remove(ele)
+	kv := ele.Value.(*entry)
+	delete(c.cache, kv.key)
+	c.usedBytes -= int64(len(kv.key)) + int64(kv.value.Len())
+}
+
+func (c *Cache) Len() int {
+	return c.ll.Len()
+}
+
+func (c *Cache) Bytes() int64 {
+	return c.usedBytes
+}
@@ -0,0 +1,58 @@
+package fifo
+
+import (
+	"testing"
+
+	"github.com/distributeCache/policy"
+)
+
+type testValue string
+
+func (v testValue) Len() int { return len(v) }
+
+func TestAddAndGet(t *testing.T) {
+	c := NewCache(0, nil)
+	c.Add("k1", testValue("v1"))
+	if v, ok := c.Get("k1"); !ok || v.(testValue) != "v1" {
+		t.Fatalf("Get(k1) = (%v, %v), want (v1, true)", v, ok)
+	}
+	if _, ok := c.Get("missing"); ok {
+		t.Fatal("Get(missing) should miss")
+	}
+}
+
+func TestEvictsInWriteOrderRegardlessOfGet(t *testing.T) {
+	var evicted []string
+	maxBytes := int64(len("k1") + len("v1") + len("k2") + len("v2"))
+	c := NewCache(maxBytes, func(key string, _ policy.Value) { evicted = append(evicted, key) })
+
+	c.Add("k1", testValue("v1"))
+	c.Add("k2", testValue("v2"))
+	// 和 LRU 不同，Get 不应该影响淘汰顺序：即便反复访问 k1，它仍然是最早写入的那个
+	c.Get("k1")
+	c.Get("k1")
+	c.Add("k3", testValue("v3"))
+
+	if len(evicted) != 1 || evicted[0] != "k1" {
+		t.Fatalf("evicted = %v, want [k1]", evicted)
+	}
+	if _, ok := c.Get("k1"); ok {
+		t.Error("k1 should have been evicted despite being the most recently accessed")
+	}
+	if _, ok := c.Get("k2"); !ok {
+		t.Error("k2 should still be present")
+	}
+}
+
+func TestRemoveDoesNotTriggerOnEvicted(t *testing.T) {
+	called := false
+	c := NewCache(0, func(string, policy.Value) { called = true })
+	c.Add("k1", testValue("v1"))
+	c.Remove("k1")
+	if called {
+		t.Error("Remove should not invoke OnEvicted")
+	}
+	if c.Len() != 0 {
+		t.Fatalf("Len() = %d, want 0", c.Len())
+	}
+}
@@ -1,38 +1,90 @@
 package distributecache
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"math/rand"
 	"sync"
+	"time"
 
+	pb "github.com/distributeCache/distributecachepb"
+	"github.com/distributeCache/policy"
 	"github.com/distributeCache/singleflight"
 )
 
+// PolicyFactory 让调用方为 Group 选择底层的淘汰策略（LRU/LFU/FIFO/TinyLFU 等）
+type PolicyFactory = policy.Factory
+
+// hotCacheRatio 决定 hotCache 最多能占用总容量的几分之一，避免其挤占 mainCache
+// 1/8 是 groupcache 的参考实现里常用的比例
+const hotCacheRatio = 8
+
+// hotCacheAdmitRate 表示从远程同伴取回的值有多大概率被写入 hotCache
+// 并非每次命中都写入，是为了避免低频 key 也占用 hotCache 的空间
+const hotCacheAdmitRate = 10
+
+// negativeCacheTTL 是确认某个 key 不存在之后的负缓存时长，
+// 在此期间内重复查询这个 key 会直接返回 ErrNotFound，不会再打到数据源（防止"击穿"）
+const negativeCacheTTL = 10 * time.Second
+
+// ttlJitterFraction 是 populateCache 给默认 TTL 添加的抖动幅度（±10%），
+// 避免大量同时写入的条目在未来同一时刻集中过期造成"雪崩"
+const ttlJitterFraction = 0.1
+
+// peerFailoverReplicas 是某个 key 在一致性哈希环上依次尝试的候选节点数量；
+// 排在最前面的节点请求失败时，换下一个候选节点重试，而不是立刻退回本地加载
+const peerFailoverReplicas = 3
+
+// defaultFetchTimeout 是共享取值调用（同伴 RPC 或本地 Getter）的默认超时。
+// 这个调用绑定的是独立于任何一个调用方的 context.Background，必须自带一个
+// 有界的超时，否则一个卡住的同伴会让它无限期挂起，既泄漏 goroutine 和连接，
+// 也没有任何调用方的 ctx 能够提前结束它——参见 load 里的说明
+const defaultFetchTimeout = 5 * time.Second
+
+// ErrNotFound 是 Getter 可以返回的哨兵错误，表示这个 key 确实不存在（而非临时性错误）；
+// Group 会对其做短 TTL 的负缓存
+var ErrNotFound = errors.New("distributecache: key not found")
+
 // Getter 接口用于获取指定键的数据
 type Getter interface {
-	Get(key string) ([]byte, error)
+	Get(ctx context.Context, key string) ([]byte, error)
 }
 
 // GetterFunc 函数类，实现 Getter 接口
 // 便于通过函数进行数据的获取
-type GetterFunc func(key string) ([]byte, error)
+type GetterFunc func(ctx context.Context, key string) ([]byte, error)
 
-func (f GetterFunc) Get(key string) ([]byte, error) {
-	return f(key)
+func (f GetterFunc) Get(ctx context.Context, key string) ([]byte, error) {
+	return f(ctx, key)
 }
 
 // Group 属于缓存名称空间，用于缓存分类和数据加载的分发
 // name: 缓存空间名称
 // getter: 获取数据的 Getter
-// mainCache: 主缓存中的数据
+// mainCache: 按一致性哈希应当由本节点持有的数据
+// hotCache: 从远程同伴取回、为了减少网络往返而在本地短期保留的热点数据
 // peers: PeerPicker 用于选择同伴
 // loader: singlefight
+// defaultTTL: populateCache 写入时使用的基准 TTL，0 表示不过期
+// fetchTimeout: 共享取值调用（同伴 RPC 或本地 Getter）的超时，默认 defaultFetchTimeout
+// negCache: 记录已确认不存在的 key 以及负缓存到期时间
 type Group struct {
-	name      string
-	getter    Getter
-	mainCache cache
-	peers     PeerPicker
-	loader    *singleflight.Group
+	name       string
+	getter     Getter
+	mainCache  cache
+	hotCache   cache
+	peers      PeerPicker
+	loader     *singleflight.Group
+	defaultTTL time.Duration
+
+	// fetchTimeout 是共享取值调用（load 里 DoChan 的 fn）的超时，默认 defaultFetchTimeout；
+	// 因为 fn 绑定的是 context.Background 而非某一个调用方的 ctx，必须自带超时才能保证有界
+	fetchTimeout time.Duration
+
+	negMu    sync.RWMutex
+	negCache map[string]time.Time
 }
 
 var (
@@ -41,21 +93,46 @@ var (
 )
 
 func NewGroup(name string, cacheBytes int64, getter Getter) *Group {
+	return NewGroupWithPolicy(name, cacheBytes, getter, nil)
+}
+
+// NewGroupWithPolicy 与 NewGroup 相同，但允许调用方指定 mainCache/hotCache 使用的淘汰策略，
+// 这样不同工作负载的 Group 可以各自选择最合适的策略（例如 LFU 更适合长尾重复访问的场景）；
+// factory 为 nil 时退化为默认的 LRU，和 NewGroup 的行为一致
+func NewGroupWithPolicy(name string, cacheBytes int64, getter Getter, factory PolicyFactory) *Group {
 	if getter == nil {
 		panic("nil Getter")
 	}
 	mu.Lock()
 	defer mu.Unlock()
+	hotBytes := hotCacheBytes(cacheBytes)
 	g := &Group{
-		name:      name,
-		getter:    getter,
-		mainCache: cache{cacheBytes: cacheBytes},
-		loader:    &singleflight.Group{},
+		name:         name,
+		getter:       getter,
+		mainCache:    cache{cacheBytes: cacheBytes - hotBytes, factory: factory},
+		hotCache:     cache{cacheBytes: hotBytes, factory: factory},
+		loader:       &singleflight.Group{},
+		fetchTimeout: defaultFetchTimeout,
 	}
 	groups[name] = g
 	return g
 }
 
+// hotCacheBytes 把总容量预算按 hotCacheRatio 分给 hotCache。0（或负数）按本仓库的
+// 约定原样保留，表示“不限制容量”；其余情况下至少分给 hotCache 1 字节，否则
+// cacheBytes < hotCacheRatio 时整除会截断成 0，而 0 恰好又是“不限制”的含义，
+// 会让小容量预算的 Group 反而得到一个无限大的 hotCache —— 与 shardBytes 要防的是
+// 同一类截断陷阱
+func hotCacheBytes(cacheBytes int64) int64 {
+	if cacheBytes <= 0 {
+		return 0
+	}
+	if b := cacheBytes / hotCacheRatio; b > 0 {
+		return b
+	}
+	return 1
+}
+
 func GetGroup(key string) *Group {
 	mu.RLock()
 	g := groups[key]
@@ -71,58 +148,218 @@ func (g *Group) RegisterPeers(peers PeerPicker) {
 	g.peers = peers
 }
 
+// SetDefaultTTL 设置 populateCache（即本地加载后写入 mainCache）使用的基准 TTL，
+// 实际写入时会在这个值上下浮动 ±10%，避免同一批 key 在未来同一时刻集体过期
+func (g *Group) SetDefaultTTL(ttl time.Duration) {
+	g.defaultTTL = ttl
+}
+
+// SetFetchTimeout 设置共享取值调用（同伴 RPC 或本地 Getter）的超时，
+// timeout <= 0 表示不设上限；默认是 defaultFetchTimeout
+func (g *Group) SetFetchTimeout(timeout time.Duration) {
+	g.fetchTimeout = timeout
+}
+
 // Get 方法根据键获取缓存中的数据;如果缓存中已经存在该值，将返回,否则将加载这个数据
-func (g *Group) Get(key string) (ByteView, error) {
+func (g *Group) Get(ctx context.Context, key string) (ByteView, error) {
+	value, _, err := g.GetWithExpiration(ctx, key)
+	return value, err
+}
+
+// GetWithExpiration 和 Get 相同，但额外返回该值的过期时间（零值表示没有设置 TTL），
+// ServeHTTP 用它把本地持有的过期时间透传给发起请求的同伴
+func (g *Group) GetWithExpiration(ctx context.Context, key string) (ByteView, time.Time, error) {
 	if key == "" {
-		return ByteView{}, fmt.Errorf("key isn't existed")
+		return ByteView{}, time.Time{}, fmt.Errorf("key isn't existed")
+	}
+	if g.isNegativelyCached(key) {
+		return ByteView{}, time.Time{}, ErrNotFound
 	}
-	if v, ok := g.mainCache.get(key); ok {
+	if v, exp, ok := g.mainCache.getWithExpiration(key); ok {
 		log.Println("[Cache hit]")
-		return v, nil
+		return v, exp, nil
+	}
+	if v, exp, ok := g.hotCache.getWithExpiration(key); ok {
+		log.Println("[Cache hit (hot)]")
+		return v, exp, nil
+	}
+	value, err := g.load(ctx, key) // 如果缓存中不存在，通过回调函数进行加载
+	if err != nil {
+		return ByteView{}, time.Time{}, err
+	}
+	// 加载完成后再查一次拿到它的过期时间：本地加载的值已写入 mainCache；从同伴取回的值
+	// 只有被 hotCache 接纳时才会写入，没有被接纳时两边都查不到，返回的过期时间是零值
+	if exp, ok := g.mainCache.peekExpiration(key); ok {
+		return value, exp, nil
 	}
-	return g.load(key) // 如果缓存中不存在，通过回调函数进行加载
+	exp, _ := g.hotCache.peekExpiration(key)
+	return value, exp, nil
 }
 
 // load 方法用于引入该键的值
-// 该值会从临远程同伴加载，不能从同伴中加载，则调用当前空间进行加载
-func (g *Group) load(key string) (value ByteView, err error) {
-	viewi, err := g.loader.Do(key, func() (any, error) {
+// 该值会从临远程同伴加载，不能从同伴中加载，则调用当前空间进行加载；
+// 用 DoChan 而不是 Do，这样 ctx 被取消时可以立即返回，不必等在途请求结束。
+//
+// 传给 fn 的是一个独立于所有调用方的 context，而不是当前这次调用的 ctx：
+// fn 在多个并发到达的同 key 调用之间是共享的，如果绑定了发起这次 DoChan 的
+// 调用方自己的 ctx，这个调用方一旦超时/取消，会错误地打断其他仍在耐心等待、
+// 自己的 ctx 远没有到期的调用方，违背了 singleflight 合并请求的初衷。
+// 每个调用方仍然各自在下面的 select 里用自己的 ctx 独立判断是否放弃等待。
+//
+// 但独立不等于没有上限：fetchCtx 仍然带着 g.fetchTimeout 的超时，否则一个
+// 卡住的同伴会让这个共享调用无限期挂起，既没有任何调用方的 ctx 能提前结束
+// 它，也会无限期占着一个 goroutine 和一条连接。
+func (g *Group) load(ctx context.Context, key string) (value ByteView, err error) {
+	ch := g.loader.DoChan(key, func() (any, error) {
+		fetchCtx := context.Background()
+		if g.fetchTimeout > 0 {
+			var cancel context.CancelFunc
+			fetchCtx, cancel = context.WithTimeout(fetchCtx, g.fetchTimeout)
+			defer cancel()
+		}
 		if g.peers != nil {
-			if peer, ok := g.peers.PickPeer(key); ok {
-				if value, err := g.getFromPeer(peer, key); err == nil {
-					return value, nil
+			// 依次尝试哈希环上排在前面的几个副本节点，一个节点请求失败时换下一个，
+			// 而不是只试一次就退回本地加载——这正是 GetN 存在的意义
+			if peers, ok := g.peers.PickPeers(key, peerFailoverReplicas); ok {
+				for _, peer := range peers {
+					if value, err := g.getFromPeer(fetchCtx, peer, key); err == nil {
+						return value, nil
+					}
+					log.Println("[Cache] failed to get from peer") // 无法从同伴获取，尝试下一个副本
 				}
-				log.Println("[Cache] failed to get from peer") // 无法从同伴获取
 			}
 		}
-		return g.getLocally(key) // 此地进行数据加载
+		return g.getLocally(fetchCtx, key) // 此地进行数据加载
 	})
-	if err == nil {
-		return viewi.(ByteView), nil
+	select {
+	case res := <-ch:
+		if res.Err != nil {
+			return ByteView{}, res.Err
+		}
+		return res.Val.(ByteView), nil
+	case <-ctx.Done():
+		// 调用方已经放弃等待，释放这个 in-flight 请求，让后续重试可以重新发起，
+		// 而不是排队等一个已经没有人等待结果的旧调用
+		g.loader.Forget(key)
+		return ByteView{}, ctx.Err()
 	}
-	return
 }
 
 // getFromPeer 方法用于从同伴中获取指定键的数据
-func (g *Group) getFromPeer(peer PeerGetter, key string) (ByteView, error) {
-	bytes, err := peer.Get(g.name, key) // 远程同伴的 HTTPGetter
+// 取回成功后，按 hotCacheAdmitRate 的概率将其写入 hotCache，
+// 这样高频被请求的远程 key 在后续请求中可以直接命中本地，省去一次网络往返
+func (g *Group) getFromPeer(ctx context.Context, peer PeerGetter, key string) (ByteView, error) {
+	req := &pb.Request{Group: g.name, Key: key}
+	res := &pb.Response{}
+	err := peer.Get(ctx, req, res) // 远程同伴的 PeerGetter
 	if err != nil {
 		return ByteView{}, err
 	}
-	return ByteView{b: bytes}, nil
+	value := ByteView{b: res.Value}
+	if rand.Intn(hotCacheAdmitRate) == 0 {
+		// 把来源节点的过期时间原样带过来，而不是重新给一个完整的 TTL，
+		// 避免 hotCache 里的副本比源数据活得更久
+		ttl := time.Duration(res.TtlSeconds) * time.Second
+		g.hotCache.addWithTTL(key, value, ttl)
+	}
+	return value, nil
 }
 
-// getLocally 方法用于此地加载数据
-func (g *Group) getLocally(key string) (ByteView, error) {
-	bytes, err := g.getter.Get(key)
+// getLocally 方法用于此地加载数据；如果 Getter 返回 ErrNotFound，
+// 会把这个 key 负缓存一段时间，避免重复请求反复打到数据源
+func (g *Group) getLocally(ctx context.Context, key string) (ByteView, error) {
+	bytes, err := g.getter.Get(ctx, key)
 	if err != nil {
-		return ByteView{}, nil
+		if errors.Is(err, ErrNotFound) {
+			g.setNegativeCache(key)
+		}
+		return ByteView{}, err
 	}
 	value := ByteView{b: cloneBytes(bytes)}
 	g.populateCache(key, value)
 	return value, nil
 }
 
+// populateCache 把本地加载到的值写入 mainCache，并附带一个带抖动的 TTL，
+// 抖动是为了防止大批同时写入的条目在未来同一时刻集中过期而引发缓存雪崩
 func (g *Group) populateCache(key string, value ByteView) {
-	g.mainCache.add(key, value)
+	g.mainCache.addWithTTL(key, value, g.jitteredTTL())
+}
+
+func (g *Group) jitteredTTL() time.Duration {
+	if g.defaultTTL <= 0 {
+		return 0
+	}
+	jitter := (rand.Float64()*2 - 1) * ttlJitterFraction // 取值范围 [-ttlJitterFraction, ttlJitterFraction]
+	return g.defaultTTL + time.Duration(float64(g.defaultTTL)*jitter)
+}
+
+func (g *Group) setNegativeCache(key string) {
+	g.negMu.Lock()
+	defer g.negMu.Unlock()
+	if g.negCache == nil {
+		g.negCache = make(map[string]time.Time)
+	}
+	g.negCache[key] = time.Now().Add(negativeCacheTTL)
+}
+
+func (g *Group) isNegativelyCached(key string) bool {
+	g.negMu.RLock()
+	until, ok := g.negCache[key]
+	g.negMu.RUnlock()
+	return ok && time.Now().Before(until)
+}
+
+// clearNegativeCache 清除某个 key 的负缓存记录，用于显式写入打断负缓存的场景：
+// 否则 GetWithExpiration 会在 isNegativelyCached 这一步就直接返回 ErrNotFound，
+// 根本不会走到下面去看 mainCache/hotCache 里这份刚写入的新值
+func (g *Group) clearNegativeCache(key string) {
+	g.negMu.Lock()
+	delete(g.negCache, key)
+	g.negMu.Unlock()
+}
+
+// AddWithTTL 主动向 mainCache 写入一个键值对，并在 ttl > 0 时设置过期时间；
+// ttl <= 0 表示不过期，行为等同于 populateCache（不带抖动）。
+// 同时清除该 key 可能存在的负缓存记录，避免之前一次 ErrNotFound 的负缓存
+// 在这次显式写入之后继续生效，掩盖掉刚刚写入的新值
+func (g *Group) AddWithTTL(key string, value ByteView, ttl time.Duration) {
+	g.clearNegativeCache(key)
+	g.mainCache.addWithTTL(key, value, ttl)
+}
+
+// StartJanitor 启动一个后台 goroutine，按 interval 周期性清理 mainCache 和 hotCache
+// 中已过期的条目，这样即使某个 key 之后再也不会被访问，也能及时释放它占用的内存；
+// 返回的 stop 用于停止该 goroutine
+func (g *Group) StartJanitor(interval time.Duration) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				now := time.Now()
+				g.mainCache.sweepExpired(now)
+				g.hotCache.sweepExpired(now)
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+// GroupStats 汇总 mainCache 和 hotCache 各自的统计信息，便于观测和容量调优
+type GroupStats struct {
+	Main CacheStats
+	Hot  CacheStats
+}
+
+// Stats 返回该 Group 当前 mainCache/hotCache 的命中率、容量占用和淘汰情况
+func (g *Group) Stats() GroupStats {
+	return GroupStats{
+		Main: g.mainCache.stats(),
+		Hot:  g.hotCache.stats(),
+	}
 }
@@ -0,0 +1,125 @@
+package distributecache
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestLoadDoesNotLetOneCallersCtxAbortSharedFetch 验证 load() 里修复过的那个 bug：
+// 多个调用方因为 singleflight 合并而共享同一次底层 fetch 时，其中一个调用方自己的
+// ctx 超时不应该打断这次 fetch，从而连累其他 ctx 还远没有到期的调用方。
+func TestLoadDoesNotLetOneCallersCtxAbortSharedFetch(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var calls int32
+
+	g := NewGroup("ctx-independence-test", 1<<20, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		atomic.AddInt32(&calls, 1)
+		close(started)
+		select {
+		case <-release:
+			return []byte("value"), nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}))
+
+	shortCtx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	type result struct {
+		v   ByteView
+		err error
+	}
+	doneA := make(chan result, 1)
+	doneB := make(chan result, 1)
+
+	go func() {
+		v, err := g.Get(shortCtx, "key")
+		doneA <- result{v, err}
+	}()
+
+	<-started // 等共享的 fetch 真正开始执行，再发起第二个调用方
+
+	go func() {
+		v, err := g.Get(context.Background(), "key")
+		doneB <- result{v, err}
+	}()
+
+	time.Sleep(40 * time.Millisecond) // 足够让 shortCtx 过期
+	close(release)                    // 放行共享的 fetch
+
+	resA := <-doneA
+	if resA.err == nil {
+		t.Error("caller A should see its own ctx deadline exceeded")
+	}
+
+	resB := <-doneB
+	if resB.err != nil {
+		t.Fatalf("caller B should not be affected by caller A's cancelled ctx, got err=%v", resB.err)
+	}
+	if resB.v.String() != "value" {
+		t.Errorf("resB.v = %q, want value", resB.v.String())
+	}
+	if atomic.LoadInt32(&calls) != 1 {
+		t.Errorf("getter called %d times, want 1 (the fetch should have been shared)", calls)
+	}
+}
+
+// TestGetCoalescesConcurrentMisses 验证并发 miss 同一个 key 时 Getter 只被调用一次，
+// 其余调用方共享这一次加载的结果。
+func TestGetCoalescesConcurrentMisses(t *testing.T) {
+	var calls int32
+	g := NewGroup("coalesce-test", 1<<20, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(20 * time.Millisecond)
+		return []byte("value"), nil
+	}))
+
+	const n = 10
+	results := make(chan ByteView, n)
+	for i := 0; i < n; i++ {
+		go func() {
+			v, err := g.Get(context.Background(), "key")
+			if err != nil {
+				t.Errorf("Get() error = %v", err)
+				return
+			}
+			results <- v
+		}()
+	}
+	for i := 0; i < n; i++ {
+		if v := <-results; v.String() != "value" {
+			t.Errorf("got %q, want value", v.String())
+		}
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("getter called %d times, want 1", got)
+	}
+}
+
+// TestGetNotFoundIsNegativelyCached 验证 ErrNotFound 会被短期负缓存，
+// 期间重复查询同一个 key 不会再打到 Getter。
+func TestGetNotFoundIsNegativelyCached(t *testing.T) {
+	var calls int32
+	g := NewGroup("negative-cache-test", 1<<20, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		atomic.AddInt32(&calls, 1)
+		return nil, ErrNotFound
+	}))
+
+	_, err := g.Get(context.Background(), "missing")
+	if err != ErrNotFound {
+		t.Fatalf("first Get() error = %v, want ErrNotFound", err)
+	}
+
+	_, err = g.Get(context.Background(), "missing")
+	if err != ErrNotFound {
+		t.Fatalf("second Get() error = %v, want ErrNotFound", err)
+	}
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("getter called %d times, want 1 (second lookup should hit the negative cache)", got)
+	}
+}
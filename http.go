@@ -1,6 +1,7 @@
 package distributecache
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"log"
@@ -8,8 +9,10 @@ import (
 	"net/url"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/distributeCache/consistenthash"
+	pb "github.com/distributeCache/distributecachepb"
 )
 
 const defaultBasePath = "/Distribute_cache"
@@ -63,16 +66,35 @@ func (p *HttpPool) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 从组中查询指定键的缓存值
-	view, err := group.Get(key)
+	// 从组中查询指定键的缓存值，连同它的过期时间一起拿到，以便透传给请求方；
+	// 用请求自身的 context：客户端断开连接时，这次请求不再等待加载结果；
+	// 被多个请求共享的那次加载本身不会因此取消，只受 Group 的 fetchTimeout 限制（见 load）
+	view, expiration, err := group.GetWithExpiration(r.Context(), key)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	// 设置返回的内容类型为 "application/octet-stream"，并将查询到的缓存值写入响应中
+	var ttlSeconds int64
+	if !expiration.IsZero() {
+		if remaining := time.Until(expiration); remaining > 0 {
+			// 向上取整：下游把 ttl_seconds == 0 解读为“永不过期”，
+			// 如果剩余时间不足 1 秒被截断成 0，会让这个条目在对端变成永久缓存
+			ttlSeconds = int64(remaining / time.Second)
+			if remaining%time.Second > 0 {
+				ttlSeconds++
+			}
+		}
+	}
+
+	// 将缓存值包装为 pb.Response 并序列化后写入响应
+	body, err := (&pb.Response{Value: view.ByteSlice(), TtlSeconds: ttlSeconds}).Marshal()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 	w.Header().Set("Content-Type", "application/octet-stream")
-	w.Write(view.ByteSlice())
+	w.Write(body)
 }
 
 // httpGetter 代表 HTTP 的客户端
@@ -81,22 +103,29 @@ type httpGetter struct {
 }
 
 // 客户端功能，通过远程请求获取指定缓存组和键对应的值
-func (h *httpGetter) Get(group string, key string) ([]byte, error) {
-	u := h.baseURL + url.QueryEscape(group) + url.QueryEscape(key)
-	res, err := http.Get(u)
+func (h *httpGetter) Get(ctx context.Context, in *pb.Request, out *pb.Response) error {
+	u := h.baseURL + url.QueryEscape(in.GetGroup()) + url.QueryEscape(in.GetKey())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	res, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer res.Body.Close()
 
 	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("server returned : %v", res.Status)
+		return fmt.Errorf("server returned : %v", res.Status)
 	}
 	bytes, err := io.ReadAll(res.Body)
 	if err != nil {
-		return nil, fmt.Errorf("reading response body : %v", err)
+		return fmt.Errorf("reading response body : %v", err)
+	}
+	if err = out.Unmarshal(bytes); err != nil {
+		return fmt.Errorf("decoding response body: %v", err)
 	}
-	return bytes, nil
+	return nil
 }
 
 var _ PeerGetter = (*httpGetter)(nil)
@@ -108,6 +137,37 @@ func (p *HttpPool) Set(peers ...string) {
 	p.peers = consistenthash.NewHash(defaultReplicas, nil)
 	p.peers.Add(peers...)
 	p.httpGetters = make(map[string]*httpGetter)
+	for _, peer := range peers {
+		p.httpGetters[peer] = &httpGetter{baseURL: peer + p.basePath}
+	}
+}
+
+// AddWeighted 注册一个节点，并给它 weight 倍于默认数量的虚拟节点，
+// 让配置更高、能承担更多流量的节点在一致性哈希环上分到更多的 key；
+// 异构集群（节点配置不一致）场景下用它替代 Set 逐个加入节点
+func (p *HttpPool) AddWeighted(peer string, weight int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.peers == nil {
+		p.peers = consistenthash.NewHash(defaultReplicas, nil)
+		p.httpGetters = make(map[string]*httpGetter)
+	}
+	p.peers.AddWeighted(peer, weight)
+	p.httpGetters[peer] = &httpGetter{baseURL: peer + p.basePath}
+}
+
+// Remove 方法将指定节点及其所有虚拟节点从一致性哈希环上移除，
+// 用于节点下线之后把流量疏散到其余节点，而不必重建整个 HttpPool
+func (p *HttpPool) Remove(peers ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.peers == nil {
+		return
+	}
+	p.peers.Remove(peers...)
+	for _, peer := range peers {
+		delete(p.httpGetters, peer)
+	}
 }
 
 // PickPeer 方法根据键选择合适的 PeerGetter,返回相应的 PeerGetter 并表示是否已找到合适的节点
@@ -121,5 +181,30 @@ func (p *HttpPool) PickPeer(key string) (PeerGetter, bool) {
 	return nil, false
 }
 
+// PickPeers 方法沿一致性哈希环返回 key 对应的最多 n 个不同候选节点（不含自己），
+// 按环上的顺序排列，供调用方在前面的节点请求失败时依次重试下一个
+func (p *HttpPool) PickPeers(key string, n int) ([]PeerGetter, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.peers == nil {
+		return nil, false
+	}
+	// 多取一个候选，这样把自己过滤掉之后仍然有机会凑够 n 个
+	candidates := p.peers.GetN(key, n+1)
+	peers := make([]PeerGetter, 0, n)
+	for _, node := range candidates {
+		if node == "" || node == p.self {
+			continue
+		}
+		if getter, ok := p.httpGetters[node]; ok {
+			peers = append(peers, getter)
+		}
+		if len(peers) == n {
+			break
+		}
+	}
+	return peers, len(peers) > 0
+}
+
 // 类型检查
 var _ PeerPicker = (*HttpPool)(nil)
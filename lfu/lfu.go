@@ -0,0 +1,136 @@
+// Package lfu 实现了最不经常使用（LFU）的淘汰策略
+package lfu
+
+import (
+	"container/heap"
+
+	"github.com/distributeCache/policy"
+)
+
+// decayThreshold 每累计这么多次 Get，就把所有条目的访问计数减半一次，
+// 避免早期的热点 key 因为历史访问次数过高，长期占着缓存不被淘汰（stale-hot-key 问题）
+const decayThreshold = 1000
+
+type entry struct {
+	key   string
+	value policy.Value
+	freq  int64
+	index int // 在堆中的位置，heap.Fix/heap.Remove 需要用到
+}
+
+// priorityQueue 是按 freq 升序排列的最小堆，堆顶是最该被淘汰的条目
+type priorityQueue []*entry
+
+func (pq priorityQueue) Len() int           { return len(pq) }
+func (pq priorityQueue) Less(i, j int) bool { return pq[i].freq < pq[j].freq }
+func (pq priorityQueue) Swap(i, j int) {
+	pq[i], pq[j] = pq[j], pq[i]
+	pq[i].index = i
+	pq[j].index = j
+}
+
+func (pq *priorityQueue) Push(x any) {
+	e := x.(*entry)
+	e.index = len(*pq)
+	*pq = append(*pq, e)
+}
+
+func (pq *priorityQueue) Pop() any {
+	old := *pq
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil
+	e.index = -1
+	*pq = old[:n-1]
+	return e
+}
+
+// Cache 是 policy.Policy 的 LFU 实现，按最小访问计数堆选择淘汰对象
+type Cache struct {
+	maxBytes  int64
+	usedBytes int64
+	items     map[string]*entry
+	pq        priorityQueue
+	gets      int64
+	OnEvicted func(key string, value policy.Value)
+}
+
+func NewCache(maxBytes int64, onEvicted func(string, policy.Value)) *Cache {
+	return &Cache{
+		maxBytes:  maxBytes,
+		items:     make(map[string]*entry),
+		OnEvicted: onEvicted,
+	}
+}
+
+// NewPolicy 将 NewCache 包装为 policy.Factory，供 Group 按策略选择 LFU
+func NewPolicy(maxBytes int64, onEvicted policy.OnEvicted) policy.Policy {
+	return NewCache(maxBytes, onEvicted)
+}
+
+func (c *Cache) Get(key string) (policy.Value, bool) {
+	e, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	e.freq++
+	heap.Fix(&c.pq, e.index)
+	c.gets++
+	c.decayIfDue()
+	return e.value, true
+}
+
+// decayIfDue 周期性地把所有访问计数减半，让旧的高频 key 逐渐让位给新的热点
+func (c *Cache) decayIfDue() {
+	if c.gets%decayThreshold != 0 {
+		return
+	}
+	for _, e := range c.pq {
+		e.freq /= 2
+	}
+	heap.Init(&c.pq)
+}
+
+func (c *Cache) Add(key string, value policy.Value) {
+	if e, ok := c.items[key]; ok {
+		c.usedBytes += int64(value.Len()) - int64(e.value.Len())
+		e.value = value
+		e.freq++
+		heap.Fix(&c.pq, e.index)
+	} else {
+		e := &entry{key: key, value: value, freq: 1}
+		heap.Push(&c.pq, e)
+		c.items[key] = e
+		c.usedBytes += int64(len(key)) + int64(value.Len())
+	}
+	for c.maxBytes > 0 && c.usedBytes > c.maxBytes && c.pq.Len() > 0 {
+		c.removeMin()
+	}
+}
+
+func (c *Cache) removeMin() {
+	e := heap.Pop(&c.pq).(*entry)
+	delete(c.items, e.key)
+	c.usedBytes -= int64(len(e.key)) + int64(e.value.Len())
+	if c.OnEvicted != nil {
+		c.OnEvicted(e.key, e.value)
+	}
+}
+
+func (c *Cache) Remove(key string) {
+	e, ok := c.items[key]
+	if !ok {
+		return
+	}
+	heap.Remove(&c.pq, e.index)
+	delete(c.items, key)
+	c.usedBytes -= int64(len(key)) + int64(e.value.Len())
+}
+
+func (c *Cache) Len() int {
+	return len(c.items)
+}
+
+func (c *Cache) Bytes() int64 {
+	return c.usedBytes
+}
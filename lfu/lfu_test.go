@@ -0,0 +1,82 @@
+package lfu
+
+import (
+	"testing"
+
+	"github.com/distributeCache/policy"
+)
+
+type testValue string
+
+func (v testValue) Len() int { return len(v) }
+
+func TestAddAndGet(t *testing.T) {
+	c := NewCache(0, nil)
+	c.Add("k1", testValue("v1"))
+	if v, ok := c.Get("k1"); !ok || v.(testValue) != "v1" {
+		t.Fatalf("Get(k1) = (%v, %v), want (v1, true)", v, ok)
+	}
+	if _, ok := c.Get("missing"); ok {
+		t.Fatal("Get(missing) should miss")
+	}
+}
+
+func TestEvictsLeastFrequentlyUsed(t *testing.T) {
+	var evicted []string
+	// 预算正好放下 k1/k2/k3 三个条目，不多不少
+	maxBytes := int64(len("k1") + len("v1") + len("k2") + len("v2") + len("k3") + len("v3"))
+	c := NewCache(maxBytes, func(key string, _ policy.Value) { evicted = append(evicted, key) })
+
+	c.Add("k1", testValue("v1"))
+	c.Add("k2", testValue("v2"))
+	c.Add("k3", testValue("v3"))
+	c.Get("k1")
+	c.Get("k1")
+	c.Get("k3")
+	// k2 从没被访问过，freq 一直停在 1；k1/k3 都被访问过，freq 更高
+
+	// 更新 k1 的值让它占用的字节数略微超出预算：这是对已有 key 的更新，
+	// 不会像插入新 key 那样引入一个 freq=1 的新条目，所以这里淘汰谁完全
+	// 取决于当前谁的访问频次最低，不存在“刚插入的新条目也是 freq=1”的平局
+	c.Add("k1", testValue("v1x"))
+
+	if len(evicted) != 1 || evicted[0] != "k2" {
+		t.Fatalf("evicted = %v, want [k2]", evicted)
+	}
+	if _, ok := c.Get("k2"); ok {
+		t.Error("k2 should have been evicted")
+	}
+	if _, ok := c.Get("k1"); !ok {
+		t.Error("k1 should still be present")
+	}
+	if _, ok := c.Get("k3"); !ok {
+		t.Error("k3 should still be present")
+	}
+}
+
+func TestRemoveDropsEntryWithoutEviction(t *testing.T) {
+	called := false
+	c := NewCache(0, func(string, policy.Value) { called = true })
+	c.Add("k1", testValue("v1"))
+	c.Remove("k1")
+	if called {
+		t.Error("Remove should not invoke OnEvicted")
+	}
+	if c.Len() != 0 {
+		t.Fatalf("Len() = %d, want 0", c.Len())
+	}
+}
+
+func TestDecayHalvesFrequencyCounts(t *testing.T) {
+	c := NewCache(0, nil)
+	c.Add("hot", testValue("v"))
+	for i := 0; i < decayThreshold; i++ {
+		c.Get("hot")
+	}
+	e := c.items["hot"]
+	// 初始 freq=1（Add），加上 decayThreshold 次 Get 后应该经历过至少一次减半，
+	// 不应该还停留在“从未衰减过”时的累计值 1+decayThreshold
+	if e.freq >= int64(1+decayThreshold) {
+		t.Errorf("freq = %d, expected decay to have reduced it below %d", e.freq, 1+decayThreshold)
+	}
+}
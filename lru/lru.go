@@ -1,7 +1,16 @@
 package lru
 
-import "container/list"
+import (
+	"container/list"
 
+	"github.com/distributeCache/policy"
+)
+
+// Value 沿用 policy.Value，要求值能够报告自身占用的字节数
+type Value = policy.Value
+
+// Cache 是 policy.Policy 的最近最少使用（LRU）实现
+// 注意：Cache 本身不是并发安全的，并发保护由上层的 distributecache.cache 负责
 // OnEvicted: 退出时调用的可选功能
 type Cache struct {
 	maxBytes  int64
@@ -17,10 +26,6 @@ type entry struct {
 	value Value
 }
 
-type Value interface {
-	Len() int
-}
-
 func NewCache(maxBytes int64, oe func(string, Value)) *Cache {
 	return &Cache{
 		maxBytes:  maxBytes,
@@ -30,6 +35,12 @@ func NewCache(maxBytes int64, oe func(string, Value)) *Cache {
 	}
 }
 
+// NewPolicy 将 NewCache 包装为 policy.Factory，供 Group 按策略选择 LRU
+func NewPolicy(maxBytes int64, onEvicted policy.OnEvicted) policy.Policy {
+	return NewCache(maxBytes, onEvicted)
+}
+
+// Get 查找 key 对应的值
 func (c *Cache) Get(key string) (value Value, ok bool) {
 	if ele, ok := c.cache[key]; ok {
 		c.ll.MoveToFront(ele)
@@ -39,19 +50,40 @@ func (c *Cache) Get(key string) (value Value, ok bool) {
 	return
 }
 
-func (c *Cache) Remove() {
+// removeElement 是实际从链表和索引中摘除条目、并触发 OnEvicted 的公共逻辑
+func (c *Cache) removeElement(ele *list.Element) {
+	c.ll.Remove(ele)
+	kv := ele.Value.(*entry)
+	delete(c.cache, kv.key)
+	c.usedBytes -= int64(len(kv.key)) + int64(kv.value.Len())
+	if c.OnEvicted != nil {
+		c.OnEvicted(kv.key, kv.value)
+	}
+}
+
+// RemoveOldest 淘汰最久未被访问的条目，在容量超限时被 Add 调用
+func (c *Cache) RemoveOldest() {
 	ele := c.ll.Back()
 	if ele != nil {
-		c.ll.Remove(ele)
-		kv := ele.Value.(*entry)
-		delete(c.cache, kv.key)
-		c.usedBytes -= int64(len(kv.key)) + int64(kv.value.Len())
-		if c.OnEvicted != nil {
-			c.OnEvicted(kv.key, kv.value)
-		}
+		c.removeElement(ele)
 	}
 }
 
+// Remove 按 key 移除一个条目，不触发 OnEvicted（调用方主动删除，而非淘汰）
+func (c *Cache) Remove(key string) {
+	ele, ok := c.cache[key]
+	if !ok {
+		return
+	}
+	c.ll.Remove(ele)
+	kv := ele.Value.(*entry)
+	delete(c.cache, kv.key)
+	c.usedBytes -= int64(len(kv.key)) + int64(kv.value.Len())
+}
+
+// Add 添加（或更新）一个键值对，占用达到或超出 maxBytes 时淘汰最久未被访问的条目；
+// 过期时间不是这一层的职责，由上层的 distributecache.cache 通过独立的
+// TTL 索引管理，这样任意一种 policy.Policy 实现都能直接获得 TTL 支持
 func (c *Cache) Add(key string, value Value) {
 	if ele, ok := c.cache[key]; ok {
 		c.ll.MoveToFront(ele)
@@ -64,10 +96,15 @@ func (c *Cache) Add(key string, value Value) {
 		c.usedBytes += int64(len(key)) + int64(value.Len())
 	}
 	for c.maxBytes > 0 && c.usedBytes >= c.maxBytes {
-		c.Remove()
+		c.RemoveOldest()
 	}
 }
 
 func (c *Cache) Len() int {
 	return c.ll.Len()
 }
+
+// Bytes 返回当前已使用的字节数，供上层统计占用情况
+func (c *Cache) Bytes() int64 {
+	return c.usedBytes
+}
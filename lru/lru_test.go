@@ -0,0 +1,75 @@
+package lru
+
+import "testing"
+
+type testValue string
+
+func (v testValue) Len() int { return len(v) }
+
+func TestAddAndGet(t *testing.T) {
+	c := NewCache(0, nil)
+	c.Add("k1", testValue("v1"))
+	if v, ok := c.Get("k1"); !ok || v.(testValue) != "v1" {
+		t.Fatalf("Get(k1) = (%v, %v), want (v1, true)", v, ok)
+	}
+	if _, ok := c.Get("missing"); ok {
+		t.Fatal("Get(missing) should miss")
+	}
+}
+
+func TestAddUpdatesExistingKey(t *testing.T) {
+	c := NewCache(0, nil)
+	c.Add("k1", testValue("v1"))
+	c.Add("k1", testValue("v1-updated"))
+	if c.Len() != 1 {
+		t.Fatalf("Len() = %d, want 1", c.Len())
+	}
+	if v, _ := c.Get("k1"); v.(testValue) != "v1-updated" {
+		t.Fatalf("Get(k1) = %v, want v1-updated", v)
+	}
+}
+
+func TestEvictsLeastRecentlyUsed(t *testing.T) {
+	var evicted []string
+	// 比 k1+k2 的总占用多一点，这样两者都能先放下，直到 k3 进来才会触发淘汰
+	maxBytes := int64(len("k1")+len("v1")+len("k2")+len("v2")) + 1
+	c := NewCache(maxBytes, func(key string, _ Value) { evicted = append(evicted, key) })
+
+	c.Add("k1", testValue("v1"))
+	c.Add("k2", testValue("v2"))
+	c.Get("k1") // 访问 k1，让它变成最近使用，k2 成为最久未使用
+	c.Add("k3", testValue("v3"))
+
+	if len(evicted) != 1 || evicted[0] != "k2" {
+		t.Fatalf("evicted = %v, want [k2]", evicted)
+	}
+	if _, ok := c.Get("k1"); !ok {
+		t.Error("k1 should still be present")
+	}
+	if _, ok := c.Get("k2"); ok {
+		t.Error("k2 should have been evicted")
+	}
+}
+
+func TestRemoveDoesNotTriggerOnEvicted(t *testing.T) {
+	called := false
+	c := NewCache(0, func(string, Value) { called = true })
+	c.Add("k1", testValue("v1"))
+	c.Remove("k1")
+	if called {
+		t.Error("Remove should not invoke OnEvicted")
+	}
+	if _, ok := c.Get("k1"); ok {
+		t.Error("k1 should be gone after Remove")
+	}
+}
+
+func TestZeroMaxBytesIsUnbounded(t *testing.T) {
+	c := NewCache(0, nil)
+	for i := 0; i < 1000; i++ {
+		c.Add(string(rune(i)), testValue("v"))
+	}
+	if c.Len() != 1000 {
+		t.Fatalf("Len() = %d, want 1000 (maxBytes=0 should mean unlimited)", c.Len())
+	}
+}
@@ -1,9 +1,29 @@
 package distributecache
 
+import (
+	"context"
+
+	pb "github.com/distributeCache/distributecachepb"
+)
+
 type PeerPicker interface {
 	PickPeer(key string) (peer PeerGetter, ok bool)
+
+	// PickPeers 按一致性哈希环上的顺序返回最多 n 个不同的候选节点（一致性哈希的
+	// GetN，不包含自己），供调用方在排在前面的节点请求失败时依次尝试下一个副本，
+	// 而不必在所有候选节点都失败之前就放弃、退回本地加载
+	PickPeers(key string, n int) (peers []PeerGetter, ok bool)
 }
 
+// PeerGetter 是节点间通信的传输层接口，HttpPool 内部使用的 httpGetter 是目前唯一的实现；
+// 请求/响应统一用 protobuf 消息承载，替换掉之前的裸字节负载，
+// 之后接入 gRPC 或 TCP 之类的传输时，只需新增一个实现该接口的类型；
+// ctx 用于把调用方的超时/取消传递到实际的网络请求中
 type PeerGetter interface {
-	Get(group string, key string) ([]byte, error)
+	Get(ctx context.Context, in *pb.Request, out *pb.Response) error
 }
+
+// Transport 是 PeerGetter 的别名：节点间通信这一层按传输方式划分实现
+// （HttpPool 用的是 HTTP），接入 gRPC/TCP 等新传输时应该实现的就是这个接口——
+// 单独起名是为了让这一点对后来者显而易见，而不必从 HttpPool 的用法反推
+type Transport = PeerGetter
@@ -0,0 +1,64 @@
+package policy_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/distributeCache/fifo"
+	"github.com/distributeCache/lfu"
+	"github.com/distributeCache/lru"
+	"github.com/distributeCache/policy"
+	"github.com/distributeCache/tinylfu"
+)
+
+// testValue 是一个占用固定字节数的测试值，方便在各策略间用同样的容量做对比
+type testValue int
+
+func (testValue) Len() int { return 8 }
+
+// zipfianKeys 生成 n 个请求，key 取自 [0, vocab)，服从 Zipf 分布，
+// 用来模拟真实世界里少数 key 占绝大多数访问量的场景
+func zipfianKeys(n int, vocab uint64) []string {
+	r := rand.New(rand.NewSource(1))
+	z := rand.NewZipf(r, 1.2, 1, vocab-1)
+	keys := make([]string, n)
+	for i := 0; i < n; i++ {
+		keys[i] = string(rune('a' + z.Uint64()%26))
+	}
+	return keys
+}
+
+// hitRate 把 trace 跑一遍给定的策略，返回命中率
+func hitRate(p policy.Policy, trace []string) float64 {
+	var hits int
+	for _, key := range trace {
+		if _, ok := p.Get(key); ok {
+			hits++
+		} else {
+			p.Add(key, testValue(0))
+		}
+	}
+	return float64(hits) / float64(len(trace))
+}
+
+var factories = map[string]policy.Factory{
+	"LRU":     lru.NewPolicy,
+	"LFU":     lfu.NewPolicy,
+	"FIFO":    fifo.NewPolicy,
+	"TinyLFU": tinylfu.NewPolicy,
+}
+
+func BenchmarkHitRateZipfian(b *testing.B) {
+	const maxBytes = 80 // 约能容纳 8 个 key（key 占 1 字节，value 占 8 字节）
+	trace := zipfianKeys(100000, 26)
+	for name, factory := range factories {
+		b.Run(name, func(b *testing.B) {
+			var rate float64
+			for i := 0; i < b.N; i++ {
+				p := factory(maxBytes, nil)
+				rate = hitRate(p, trace)
+			}
+			b.ReportMetric(rate*100, "hit-%")
+		})
+	}
+}
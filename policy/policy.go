@@ -0,0 +1,24 @@
+// Package policy 定义了缓存淘汰策略的统一接口，
+// 让 lru、lfu、fifo、tinylfu 等实现可以在 Group 中互相替换
+package policy
+
+// Value 接口要求缓存的值能够报告自身占用的字节数，用于容量统计
+type Value interface {
+	Len() int
+}
+
+// Policy 是所有淘汰策略需要实现的统一接口
+type Policy interface {
+	Add(key string, value Value)
+	Get(key string) (value Value, ok bool)
+	Remove(key string)
+	Len() int
+	Bytes() int64
+}
+
+// OnEvicted 在一个键值对被淘汰时回调，用于统计或清理
+type OnEvicted func(key string, value Value)
+
+// Factory 根据最大字节数和淘汰回调创建一个 Policy 实例，
+// Group 通过它在不同的淘汰策略之间切换，而不需要关心具体实现
+type Factory func(maxBytes int64, onEvicted OnEvicted) Policy
@@ -0,0 +1,126 @@
+package distributecache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/distributeCache/lru"
+	"github.com/distributeCache/policy"
+)
+
+// shard 持有 cache 的一个分片：独立的互斥锁、独立的淘汰策略实例和独立的 TTL 索引，
+// 这样不同分片上的操作可以完全并行，不再互相竞争同一把锁
+//
+// ttls 单独记录每个 key 的过期时间，不要求底层 policy 理解 TTL 的概念，
+// 这样任意一种淘汰策略都能直接获得 TTL 支持
+type shard struct {
+	mu        sync.Mutex
+	policy    policy.Policy
+	ttls      map[string]time.Time // key -> 过期时间，key 不存在表示没有设置 TTL
+	hits      int64
+	misses    int64
+	evictions int64
+}
+
+// ensurePolicy 在该分片的 policy 还没有创建时，用 cacheBytes/factory 延迟创建一个
+func (s *shard) ensurePolicy(cacheBytes int64, factory policy.Factory) {
+	if s.policy != nil {
+		return
+	}
+	if factory == nil {
+		factory = lru.NewPolicy // 未指定策略时默认使用 LRU，和引入 Policy 抽象之前保持一致的行为
+	}
+	s.policy = factory(cacheBytes, func(key string, value policy.Value) {
+		s.evictions++ // 每次淘汰都计数，用于 Stats 展示
+	})
+}
+
+func (s *shard) add(key string, value ByteView, cacheBytes int64, factory policy.Factory) {
+	s.addWithTTL(key, value, 0, cacheBytes, factory)
+}
+
+func (s *shard) addWithTTL(key string, value ByteView, ttl time.Duration, cacheBytes int64, factory policy.Factory) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ensurePolicy(cacheBytes, factory)
+	s.policy.Add(key, value)
+	if s.ttls == nil {
+		s.ttls = make(map[string]time.Time)
+	}
+	if ttl > 0 {
+		s.ttls[key] = time.Now().Add(ttl)
+	} else {
+		delete(s.ttls, key) // 不带 TTL 的写入要清掉旧的过期时间，避免覆盖写入后仍被当成过期数据淘汰
+	}
+}
+
+func (s *shard) get(key string) (value ByteView, ok bool) {
+	value, _, ok = s.getWithExpiration(key)
+	return
+}
+
+func (s *shard) getWithExpiration(key string) (value ByteView, expiration time.Time, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.policy == nil {
+		s.misses++
+		return // 如果策略实例还没有创建，返回空值
+	}
+	if exp, has := s.ttls[key]; has {
+		if time.Now().After(exp) {
+			// 已过期，当作未命中处理，顺带把它从底层 policy 中清掉
+			s.policy.Remove(key)
+			delete(s.ttls, key)
+			s.misses++
+			return
+		}
+		expiration = exp
+	}
+	if v, ok := s.policy.Get(key); ok {
+		s.hits++
+		return v.(ByteView), expiration, ok // 转为 ByteView 类型并返回
+	}
+	s.misses++
+	return
+}
+
+func (s *shard) peekExpiration(key string) (expiration time.Time, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	expiration, ok = s.ttls[key]
+	return
+}
+
+func (s *shard) sweepExpired(now time.Time) (removed int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.policy == nil {
+		return 0
+	}
+	for key, exp := range s.ttls {
+		if now.After(exp) {
+			s.policy.Remove(key)
+			delete(s.ttls, key)
+			removed++
+		}
+	}
+	return removed
+}
+
+func (s *shard) stats() CacheStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var bytes int64
+	var length int
+	if s.policy != nil {
+		bytes = s.policy.Bytes()
+		length = s.policy.Len()
+	}
+	return CacheStats{
+		Hits:      s.hits,
+		Misses:    s.misses,
+		Bytes:     bytes,
+		Evictions: s.evictions,
+		Len:       length,
+	}
+}
@@ -6,11 +6,22 @@ import "sync"
 // wg: 使用 sync.WaitGroup 来等待并发请求的结束
 // val: 返回的结果值
 // err: 返回的错误信息
+// forgotten: 标记这个 call 是否已经被 Forget 提前从 Group.m 中摘除，
+// 避免它运行结束后再次误删一个同名 key 对应的新 call
 
 type call struct {
-	wg  sync.WaitGroup
-	val any
-	err error
+	wg        sync.WaitGroup
+	val       any
+	err       error
+	forgotten bool
+}
+
+// Result 是 DoChan 异步返回的结果
+// Shared 表示这次调用到达时已经有同 key 的请求在执行，结果是共享来的
+type Result struct {
+	Val    any
+	Err    error
+	Shared bool
 }
 
 // Group 用于管理一组正在运行的请求
@@ -28,15 +39,27 @@ type Group struct {
 // 返回函数的返回值，包括返回结果和错误
 
 func (g *Group) Do(key string, fn func() (any, error)) (any, error) {
+	res := <-g.DoChan(key, fn)
+	return res.Val, res.Err
+}
+
+// DoChan 与 Do 相同，但立即返回一个 channel，调用方可以配合 select 实现超时或取消，
+// 而不必一直阻塞在这次调用上
+func (g *Group) DoChan(key string, fn func() (any, error)) <-chan Result {
+	ch := make(chan Result, 1)
+
 	g.mu.Lock() // 加互斥锁，以保证多人并发操作的安全
 	if g.m == nil {
 		g.m = make(map[string]*call) // 如果应用 map 为 nil，创建 map
 	}
 	if c, ok := g.m[key]; ok {
-		// 如果该 key 的请求已经在 map 中，表明该请求已经被执行，就等待它结束
+		// 如果该 key 的请求已经在 map 中，表明该请求已经被执行，另起一个 goroutine 等它结束
 		g.mu.Unlock()
-		c.wg.Wait()         // 等待对应请求的完成
-		return c.val, c.err // 返回请求的结果
+		go func() {
+			c.wg.Wait() // 等待对应请求的完成
+			ch <- Result{Val: c.val, Err: c.err, Shared: true}
+		}()
+		return ch
 	}
 	// 否则，创建一个新的 call 实例
 	c := new(call)
@@ -44,15 +67,33 @@ func (g *Group) Do(key string, fn func() (any, error)) (any, error) {
 	g.m[key] = c  // 将这个 call 设置为当前请求的值
 	g.mu.Unlock() // 锁释放
 
+	go g.doCall(c, key, fn, ch)
+	return ch
+}
+
+func (g *Group) doCall(c *call, key string, fn func() (any, error), ch chan Result) {
 	// 执行函数，获取返回值和错误
 	c.val, c.err = fn()
 	c.wg.Done() // 调用 Done 来使等待的中任完成
 
-	// 开始写销，将该请求从 map 中删除
+	// 开始写销，将该请求从 map 中删除；如果调用方已经 Forget 过，说明 map 里
+	// 现在可能是同一个 key 的新 call，不能把它也删掉
 	g.mu.Lock()
-	delete(g.m, key) // 将完成的 call 从 map 中删除
+	if !c.forgotten {
+		delete(g.m, key)
+	}
 	g.mu.Unlock()
 
-	// 返回请求的值
-	return c.val, c.err
+	ch <- Result{Val: c.val, Err: c.err, Shared: false}
+}
+
+// Forget 把 key 对应的在途请求从 Group 中移除，之后发起的同 key 调用会重新执行 fn，
+// 而不是继续等待这个已经没有人关心结果的旧请求；典型场景是调用方因为 ctx 取消而放弃等待
+func (g *Group) Forget(key string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if c, ok := g.m[key]; ok {
+		c.forgotten = true
+	}
+	delete(g.m, key)
 }
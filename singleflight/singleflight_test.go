@@ -0,0 +1,113 @@
+package singleflight
+
+import (
+	"sync/atomic"
+	"testing"
+)
+
+func TestDoChanCoalescesConcurrentCalls(t *testing.T) {
+	var g Group
+	var calls int32
+	start := make(chan struct{})
+	release := make(chan struct{})
+
+	fn := func() (any, error) {
+		atomic.AddInt32(&calls, 1)
+		close(start)
+		<-release
+		return "value", nil
+	}
+
+	ch1 := g.DoChan("key", fn)
+	<-start // 等第一个调用真正进入 fn，再发起第二个同 key 调用
+
+	fn2Called := false
+	ch2 := g.DoChan("key", func() (any, error) {
+		fn2Called = true
+		return "should not run", nil
+	})
+
+	close(release)
+
+	res1 := <-ch1
+	res2 := <-ch2
+
+	if atomic.LoadInt32(&calls) != 1 {
+		t.Fatalf("fn called %d times, want 1", calls)
+	}
+	if fn2Called {
+		t.Error("second caller's fn should never run: it should share the first call's result")
+	}
+	if res1.Val != "value" || res2.Val != "value" {
+		t.Fatalf("got res1=%+v res2=%+v, want both Val=value", res1, res2)
+	}
+	if res1.Shared {
+		t.Error("the call that actually ran fn should not be marked Shared")
+	}
+	if !res2.Shared {
+		t.Error("the coalesced caller should be marked Shared")
+	}
+}
+
+func TestForgetLetsAFreshCallRunIndependently(t *testing.T) {
+	var g Group
+	var calls int32
+	block := make(chan struct{})
+
+	ch1 := g.DoChan("key", func() (any, error) {
+		atomic.AddInt32(&calls, 1)
+		<-block
+		return "first", nil
+	})
+
+	g.Forget("key") // 典型场景：调用方因为 ctx 取消而放弃等待
+
+	ch2 := g.DoChan("key", func() (any, error) {
+		atomic.AddInt32(&calls, 1)
+		return "second", nil
+	})
+
+	res2 := <-ch2
+	if res2.Val != "second" {
+		t.Fatalf("res2.Val = %v, want second: Forget should let a fresh call run instead of waiting on the forgotten one", res2.Val)
+	}
+
+	close(block)
+	res1 := <-ch1
+	if res1.Val != "first" {
+		t.Fatalf("res1.Val = %v, want first", res1.Val)
+	}
+
+	if atomic.LoadInt32(&calls) != 2 {
+		t.Fatalf("calls = %d, want 2 (the forgotten call must still run to completion on its own)", calls)
+	}
+}
+
+func TestDoDeduplicatesConcurrentCalls(t *testing.T) {
+	var g Group
+	var calls int32
+	const n = 20
+
+	results := make(chan any, n)
+	for i := 0; i < n; i++ {
+		go func() {
+			v, err := g.Do("key", func() (any, error) {
+				atomic.AddInt32(&calls, 1)
+				return "value", nil
+			})
+			if err != nil {
+				t.Errorf("Do() error = %v", err)
+				return
+			}
+			results <- v
+		}()
+	}
+	for i := 0; i < n; i++ {
+		if v := <-results; v != "value" {
+			t.Errorf("got %v, want value", v)
+		}
+	}
+	if got := atomic.LoadInt32(&calls); got == 0 || got > n {
+		t.Errorf("calls = %d, want between 1 and %d", got, n)
+	}
+}
@@ -0,0 +1,76 @@
+package tinylfu
+
+import "hash/fnv"
+
+// sketch 是一个简化的 Count-Min Sketch，用来低成本地估算一个 key 的近似访问频率，
+// W-TinyLFU 用它在准入过滤时比较候选者和被淘汰者谁更"热"
+type sketch struct {
+	width     uint32
+	rows      [depth][]uint8
+	seeds     [depth]uint32
+	additions int64
+	resetAt   int64
+}
+
+const depth = 4
+
+func newSketch(width uint32) *sketch {
+	if width == 0 {
+		width = 1024
+	}
+	s := &sketch{
+		width:   width,
+		seeds:   [depth]uint32{0x9e3779b9, 0x85ebca6b, 0xc2b2ae35, 0x27d4eb2f},
+		resetAt: int64(width) * 10, // 累计计数达到一定规模后整体衰减一次，防止计数器饱和、老化过期的热点
+	}
+	for i := range s.rows {
+		s.rows[i] = make([]uint8, width)
+	}
+	return s
+}
+
+func (s *sketch) index(row int, key string) uint32 {
+	h := fnv32(key) ^ s.seeds[row]
+	return h % s.width
+}
+
+// add 记录一次对 key 的访问
+func (s *sketch) add(key string) {
+	for i := 0; i < depth; i++ {
+		idx := s.index(i, key)
+		if s.rows[i][idx] < 255 {
+			s.rows[i][idx]++
+		}
+	}
+	s.additions++
+	if s.additions >= s.resetAt {
+		s.reset()
+	}
+}
+
+// estimate 返回 key 的近似访问频率（取各行计数的最小值，这是 Count-Min Sketch 的标准做法）
+func (s *sketch) estimate(key string) uint8 {
+	min := uint8(255)
+	for i := 0; i < depth; i++ {
+		if c := s.rows[i][s.index(i, key)]; c < min {
+			min = c
+		}
+	}
+	return min
+}
+
+// reset 将所有计数减半，是一种简单的老化机制，避免早期的热点长期压制新的热点
+func (s *sketch) reset() {
+	for i := range s.rows {
+		for j := range s.rows[i] {
+			s.rows[i][j] /= 2
+		}
+	}
+	s.additions = 0
+}
+
+func fnv32(key string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return h.Sum32()
+}
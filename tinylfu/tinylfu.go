@@ -0,0 +1,193 @@
+// Package tinylfu 实现了 W-TinyLFU：一个小的 window LRU 加一个准入过滤的主 LRU，
+// 用 Count-Min Sketch 估算的访问频率来决定从 window 淘汰出来的候选者是否值得进入主缓存
+package tinylfu
+
+import (
+	"container/list"
+
+	"github.com/distributeCache/policy"
+)
+
+// windowRatio 是 window 段占总容量的比例，1% 是 W-TinyLFU 论文里推荐的默认值
+const windowRatio = 100
+
+type entry struct {
+	key   string
+	value policy.Value
+}
+
+// Cache 是 policy.Policy 的 W-TinyLFU 实现
+type Cache struct {
+	maxBytes    int64
+	windowBytes int64
+
+	usedWindowBytes int64
+	windowLL        *list.List
+	windowMap       map[string]*list.Element
+
+	usedMainBytes int64
+	mainLL        *list.List
+	mainMap       map[string]*list.Element
+
+	sketch    *sketch
+	OnEvicted func(key string, value policy.Value)
+}
+
+func NewCache(maxBytes int64, onEvicted func(string, policy.Value)) *Cache {
+	windowBytes := maxBytes / windowRatio
+	if windowBytes == 0 && maxBytes > 0 {
+		windowBytes = 1
+	}
+	return &Cache{
+		maxBytes:    maxBytes,
+		windowBytes: windowBytes,
+		windowLL:    list.New(),
+		windowMap:   make(map[string]*list.Element),
+		mainLL:      list.New(),
+		mainMap:     make(map[string]*list.Element),
+		sketch:      newSketch(1024),
+		OnEvicted:   onEvicted,
+	}
+}
+
+// NewPolicy 将 NewCache 包装为 policy.Factory，供 Group 按策略选择 W-TinyLFU
+func NewPolicy(maxBytes int64, onEvicted policy.OnEvicted) policy.Policy {
+	return NewCache(maxBytes, onEvicted)
+}
+
+func (c *Cache) Get(key string) (policy.Value, bool) {
+	c.sketch.add(key)
+	if ele, ok := c.windowMap[key]; ok {
+		c.windowLL.MoveToFront(ele)
+		return ele.Value.(*entry).value, true
+	}
+	if ele, ok := c.mainMap[key]; ok {
+		c.mainLL.MoveToFront(ele)
+		return ele.Value.(*entry).value, true
+	}
+	return nil, false
+}
+
+func (c *Cache) Add(key string, value policy.Value) {
+	c.sketch.add(key)
+	if ele, ok := c.windowMap[key]; ok {
+		c.windowLL.MoveToFront(ele)
+		kv := ele.Value.(*entry)
+		c.usedWindowBytes += int64(value.Len()) - int64(kv.value.Len())
+		kv.value = value
+		c.shrinkWindow()
+		return
+	}
+	if ele, ok := c.mainMap[key]; ok {
+		c.mainLL.MoveToFront(ele)
+		kv := ele.Value.(*entry)
+		c.usedMainBytes += int64(value.Len()) - int64(kv.value.Len())
+		kv.value = value
+		c.shrinkMain()
+		return
+	}
+	// 新 key 一律先进入 window，只有扛过了准入过滤才有机会进入 main
+	ele := c.windowLL.PushFront(&entry{key, value})
+	c.windowMap[key] = ele
+	c.usedWindowBytes += int64(len(key)) + int64(value.Len())
+	c.shrinkWindow()
+}
+
+// shrinkWindow 把超出 window 预算的条目逐个淘汰出 window，并交给准入过滤决定去留
+func (c *Cache) shrinkWindow() {
+	for c.windowBytes > 0 && c.usedWindowBytes > c.windowBytes {
+		ele := c.windowLL.Back()
+		if ele == nil {
+			break
+		}
+		c.windowLL.Remove(ele)
+		kv := ele.Value.(*entry)
+		delete(c.windowMap, kv.key)
+		c.usedWindowBytes -= int64(len(kv.key)) + int64(kv.value.Len())
+		c.admit(kv.key, kv.value)
+	}
+}
+
+// admit 是 TinyLFU 的准入过滤：main 未满时直接放入；main 已满时，
+// 只有候选者的估计访问频率高于 main 中最久未使用条目（victim）时才替换它，
+// 否则候选者被直接丢弃——这正是 TinyLFU 避免被一次性扫描污染缓存的关键
+func (c *Cache) admit(key string, value policy.Value) {
+	mainBudget := c.maxBytes - c.windowBytes
+	if mainBudget <= 0 {
+		if c.OnEvicted != nil {
+			c.OnEvicted(key, value)
+		}
+		return
+	}
+	if c.usedMainBytes+int64(len(key))+int64(value.Len()) <= mainBudget {
+		c.insertMain(key, value)
+		return
+	}
+	victimEle := c.mainLL.Back()
+	if victimEle == nil {
+		c.insertMain(key, value)
+		return
+	}
+	victim := victimEle.Value.(*entry)
+	if c.sketch.estimate(key) <= c.sketch.estimate(victim.key) {
+		if c.OnEvicted != nil {
+			c.OnEvicted(key, value) // 候选者不够热门，丢弃
+		}
+		return
+	}
+	c.mainLL.Remove(victimEle)
+	delete(c.mainMap, victim.key)
+	c.usedMainBytes -= int64(len(victim.key)) + int64(victim.value.Len())
+	if c.OnEvicted != nil {
+		c.OnEvicted(victim.key, victim.value)
+	}
+	c.insertMain(key, value)
+}
+
+func (c *Cache) insertMain(key string, value policy.Value) {
+	ele := c.mainLL.PushFront(&entry{key, value})
+	c.mainMap[key] = ele
+	c.usedMainBytes += int64(len(key)) + int64(value.Len())
+	c.shrinkMain()
+}
+
+func (c *Cache) shrinkMain() {
+	mainBudget := c.maxBytes - c.windowBytes
+	for mainBudget > 0 && c.usedMainBytes > mainBudget {
+		ele := c.mainLL.Back()
+		if ele == nil {
+			break
+		}
+		c.mainLL.Remove(ele)
+		kv := ele.Value.(*entry)
+		delete(c.mainMap, kv.key)
+		c.usedMainBytes -= int64(len(kv.key)) + int64(kv.value.Len())
+		if c.OnEvicted != nil {
+			c.OnEvicted(kv.key, kv.value)
+		}
+	}
+}
+
+func (c *Cache) Remove(key string) {
+	if ele, ok := c.windowMap[key]; ok {
+		c.windowLL.Remove(ele)
+		kv := ele.Value.(*entry)
+		delete(c.windowMap, key)
+		c.usedWindowBytes -= int64(len(kv.key)) + int64(kv.value.Len())
+		return
+	}
+	if ele, ok := c.mainMap[key]; ok {
+		c.mainLL.Remove(ele)
+		kv := ele.Value.(*entry)
+		delete(c.mainMap, key)
+		c.usedMainBytes -= int64(len(kv.key)) + int64(kv.value.Len())
+	}
+}
+
+func (c *Cache) Len() int {
+	return c.windowLL.Len() + c.mainLL.Len()
+}
+
+func (c *Cache) Bytes() int64 {
+	return c.usedWindowBytes + c.usedMainBytes
+}
@@ -0,0 +1,70 @@
+package tinylfu
+
+import "testing"
+
+type testValue string
+
+func (v testValue) Len() int { return len(v) }
+
+func TestAddAndGet(t *testing.T) {
+	c := NewCache(0, nil)
+	c.Add("k1", testValue("v1"))
+	if v, ok := c.Get("k1"); !ok || v.(testValue) != "v1" {
+		t.Fatalf("Get(k1) = (%v, %v), want (v1, true)", v, ok)
+	}
+	if _, ok := c.Get("missing"); ok {
+		t.Fatal("Get(missing) should miss")
+	}
+}
+
+func TestEntryFlowsFromWindowIntoMain(t *testing.T) {
+	// windowBytes 很小（只能放 1 字节），任何真实大小的条目都会立刻被挤出 window，
+	// 交给 admit 决定去留；main 这边预算充足，应该直接放行
+	c := NewCache(10, nil)
+	c.Add("k1", testValue("v1"))
+
+	if _, ok := c.windowMap["k1"]; ok {
+		t.Error("k1 should have been pushed out of the window already")
+	}
+	if _, ok := c.mainMap["k1"]; !ok {
+		t.Error("k1 should have been admitted into main")
+	}
+	if v, ok := c.Get("k1"); !ok || v.(testValue) != "v1" {
+		t.Fatalf("Get(k1) = (%v, %v), want (v1, true)", v, ok)
+	}
+}
+
+func TestAdmissionFilterPrefersHotterCandidate(t *testing.T) {
+	// windowBytes=1，mainBudget=4，"vk"+"vv" 正好 4 字节，刚好能让 main 被占满
+	c := NewCache(5, nil)
+	c.Add("vk", testValue("vv"))
+	if _, ok := c.mainMap["vk"]; !ok {
+		t.Fatal("setup failed: vk should have been admitted into main first")
+	}
+
+	// 用 Get 反复"预热" ck 在 sketch 里的估计频率，此时它还没有被写入缓存
+	for i := 0; i < 20; i++ {
+		c.Get("ck")
+	}
+
+	c.Add("ck", testValue("cc")) // "ck"+"cc" 同样正好 4 字节，替换后不会把自己也挤出去
+
+	if _, ok := c.mainMap["ck"]; !ok {
+		t.Error("ck should have replaced vk in main: it was estimated far hotter")
+	}
+	if _, ok := c.mainMap["vk"]; ok {
+		t.Error("vk should have been evicted from main by the hotter candidate")
+	}
+}
+
+func TestRemove(t *testing.T) {
+	c := NewCache(0, nil)
+	c.Add("k1", testValue("v1"))
+	c.Remove("k1")
+	if _, ok := c.Get("k1"); ok {
+		t.Error("k1 should be gone after Remove")
+	}
+	if c.Len() != 0 {
+		t.Fatalf("Len() = %d, want 0", c.Len())
+	}
+}